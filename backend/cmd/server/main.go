@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
@@ -10,18 +12,82 @@ import (
 	"time"
 
 	"github.com/gravitek/crossplane-spy/internal/api"
+	"github.com/gravitek/crossplane-spy/internal/history"
 	"github.com/gravitek/crossplane-spy/internal/k8s"
+	"github.com/gravitek/crossplane-spy/internal/report"
+	"github.com/gravitek/crossplane-spy/internal/snapshot"
+	"github.com/gravitek/crossplane-spy/internal/storage"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Offline mode flag
+var offlinePath = flag.String("offline", "", "Serve the objects of a directory or tarball of YAML/JSON manifests (e.g. a support bundle) instead of a cluster")
+
+// Storage flags
+var (
+	storeType      = flag.String("store", "memory", "Store for observed history and snapshots: memory or bolt (persistent)")
+	storePath      = flag.String("store-path", "/data/crossplane-spy.db", "Path of the database file when --store=bolt")
+	storeRetention = flag.Duration("store-retention", storage.DefaultRetention, "How long persisted records are kept, 0 keeps them forever")
+	storeMaxSizeMB = flag.Int64("store-max-size-mb", storage.DefaultMaxSizeBytes/(1024*1024), "Maximum size of persisted records in MiB, 0 disables the cap")
+
+	snapshotInterval = flag.Duration("snapshot-interval", 0, "Interval between scheduled snapshots, 0 disables them")
 )
 
 func main() {
-	// Initialize Kubernetes client
-	k8sClient, err := k8s.NewClient()
+	flag.Parse()
+
+	// Initialize Kubernetes client, or load the objects of a bundle in offline mode
+	var k8sClient *k8s.Client
+	var err error
+	if *offlinePath != "" {
+		k8sClient, err = k8s.NewOfflineClient(*offlinePath)
+	} else {
+		k8sClient, err = k8s.NewClient()
+	}
 	if err != nil {
 		log.Fatalf("Failed to initialize Kubernetes client: %v", err)
 	}
 
+	// Invalidate cached discovery data when CRDs or XRDs are added or removed
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
+	if err := k8sClient.StartDiscoveryWatch(watchCtx); err != nil {
+		log.Printf("Discovery watch disabled, discovery cache will not be invalidated: %v", err)
+	}
+
+	// Keep an in-memory copy of all Crossplane objects for search
+	objectCache := k8s.NewObjectCache(k8sClient)
+
+	// Record the condition transitions observed by the object cache, and the Events about those objects
+	historyStore, snapshotStore, closeStore, err := newStores(watchCtx)
+	if err != nil {
+		log.Fatalf("Failed to initialize store: %v", err)
+	}
+	defer closeStore()
+	recorder := history.NewRecorder(historyStore)
+	objectCache.AddEventHandler(recorder)
+
+	// Count the spec updates of cached objects to detect drifting managed resources
+	churn := report.NewChurnTracker(report.DefaultChurnWindow)
+	objectCache.AddEventHandler(churn)
+	objectCache.Start(watchCtx)
+
+	// Events are only recorded for objects of the cache, so watch them once it is filled
+	go func() {
+		if !cache.WaitForCacheSync(watchCtx.Done(), objectCache.HasSynced) {
+			return
+		}
+		if err := k8sClient.StartEventWatch(watchCtx, recorder); err != nil {
+			log.Printf("Event watch disabled, events will not be recorded: %v", err)
+		}
+	}()
+
+	if *snapshotInterval > 0 {
+		snapshot.Schedule(watchCtx, objectCache, snapshotStore, *snapshotInterval)
+	}
+
 	// Initialize API server
-	router := api.NewRouter(k8sClient)
+	router := api.NewRouter(k8sClient, objectCache, historyStore, snapshotStore, churn)
 
 	// Configure server
 	port := os.Getenv("PORT")
@@ -62,3 +128,31 @@ func main() {
 
 	log.Println("Server exited")
 }
+
+// newStores creates the history and snapshot stores selected by the storage flags
+// The returned function closes the stores on shutdown
+func newStores(ctx context.Context) (history.Store, snapshot.Store, func(), error) {
+	switch *storeType {
+	case "memory":
+		historyStore := history.NewMemoryStore(history.DefaultMaxTransitionsPerObject, history.DefaultMaxObjects)
+		return historyStore, snapshot.NewMemoryStore(snapshot.DefaultMaxSnapshots), func() {}, nil
+	case "bolt":
+		db, err := storage.Open(*storePath, storage.Options{
+			Retention:    *storeRetention,
+			MaxSizeBytes: *storeMaxSizeMB * 1024 * 1024,
+		})
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		db.StartRetention(ctx, storage.DefaultRetentionInterval)
+		log.Printf("Persisting history and snapshots to %s (retention %s, max size %d MiB)", *storePath, *storeRetention, *storeMaxSizeMB)
+
+		return db, db, func() {
+			if err := db.Close(); err != nil {
+				log.Printf("Error closing store: %v", err)
+			}
+		}, nil
+	default:
+		return nil, nil, nil, fmt.Errorf("unknown store %q: must be memory or bolt", *storeType)
+	}
+}
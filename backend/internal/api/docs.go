@@ -129,6 +129,15 @@ func apiDocs(c *gin.Context) {
                 All endpoints return JSON data.
             </div>
 
+            <div class="info-box">
+                <strong>🔎 List Filters</strong><br>
+                All list endpoints accept <code>labelSelector</code>, <code>fieldSelector</code>, <code>namespace</code>,
+                <code>kind</code>, <code>name</code> (substring, or <code>/regex/</code>), <code>ready</code> and <code>synced</code>
+                query parameters, e.g. <a href="/api/v1/xrs?ready=false" target="_blank">/api/v1/xrs?ready=false</a>.<br>
+                Results are paged with <code>limit</code> and the <code>continue</code> token of the previous response,
+                and ordered with <code>sort=name|age|namespace|ready</code> (prefix with <code>-</code> to reverse).
+            </div>
+
             <div class="section">
                 <h2>System</h2>
                 <div class="endpoint">
@@ -156,6 +165,18 @@ func apiDocs(c *gin.Context) {
                     <div class="description">List all Provider packages</div>
                 </div>
 
+                <div class="endpoint">
+                    <span class="method">GET</span>
+                    <span class="path">/api/v1/providers/:name</span>
+                    <div class="description">Get a Provider with its DeploymentRuntimeConfig, Deployment, pods, restarts and last termination reasons</div>
+                </div>
+
+                <div class="endpoint">
+                    <span class="method">GET</span>
+                    <span class="path">/api/v1/providers/:name/logs</span>
+                    <div class="description">Stream the logs of the Provider pods as server-sent events (?tailLines=, ?sinceSeconds=, ?container=, ?follow=true)</div>
+                </div>
+
                 <div class="endpoint">
                     <span class="method">GET</span>
                     <span class="path"><a href="/api/v1/xrds" target="_blank">/api/v1/xrds</a></span>
@@ -168,16 +189,58 @@ func apiDocs(c *gin.Context) {
                     <div class="description">List all Compositions</div>
                 </div>
 
+                <div class="endpoint">
+                    <span class="method">GET</span>
+                    <span class="path">/api/v1/compositions/:name</span>
+                    <div class="description">Get a Composition with its EnvironmentConfig selectors resolved to the configs they match</div>
+                </div>
+
+                <div class="endpoint">
+                    <span class="method">GET</span>
+                    <span class="path">/api/v1/compositions/:name/revisions</span>
+                    <div class="description">Get the CompositionRevision history of a Composition with the pipeline and resources diff between revisions</div>
+                </div>
+
+                <div class="endpoint">
+                    <span class="method">GET</span>
+                    <span class="path">/api/v1/compositions/:name/lint</span>
+                    <div class="description">Run static checks against a Composition: missing Functions, duplicate steps, XRD and version of its composite type, legacy Resources mode and unknown patch fields</div>
+                </div>
+
+                <div class="endpoint">
+                    <span class="method">GET</span>
+                    <span class="path"><a href="/api/v1/environmentconfigs" target="_blank">/api/v1/environmentconfigs</a></span>
+                    <div class="description">List all EnvironmentConfigs with their data keys</div>
+                </div>
+
+                <div class="endpoint">
+                    <span class="method">GET</span>
+                    <span class="path">/api/v1/environmentconfigs/:name</span>
+                    <div class="description">Get an EnvironmentConfig with its data</div>
+                </div>
+
                 <div class="endpoint">
                     <span class="method">GET</span>
                     <span class="path"><a href="/api/v1/functions" target="_blank">/api/v1/functions</a></span>
                     <div class="description">List all Composition Functions</div>
                 </div>
 
+                <div class="endpoint">
+                    <span class="method">GET</span>
+                    <span class="path">/api/v1/functions/:name</span>
+                    <div class="description">Get a Function with its DeploymentRuntimeConfig, Deployment, pods, restarts and last termination reasons</div>
+                </div>
+
+                <div class="endpoint">
+                    <span class="method">GET</span>
+                    <span class="path">/api/v1/functions/:name/logs</span>
+                    <div class="description">Stream the logs of the Function pods as server-sent events (?tailLines=, ?sinceSeconds=, ?container=, ?follow=true)</div>
+                </div>
+
                 <div class="endpoint">
                     <span class="method">GET</span>
                     <span class="path"><a href="/api/v1/providerconfigs" target="_blank">/api/v1/providerconfigs</a></span>
-                    <div class="description">List all ProviderConfigs</div>
+                    <div class="description">List all ProviderConfigs with their credentials source, users count and ProviderConfigUsages</div>
                 </div>
             </div>
 
@@ -187,7 +250,127 @@ func apiDocs(c *gin.Context) {
                 <div class="endpoint">
                     <span class="method">GET</span>
                     <span class="path"><a href="/api/v1/xrs" target="_blank">/api/v1/xrs</a></span>
-                    <div class="description">List all Composite Resource (XR) instances</div>
+                    <div class="description">List all Composite Resource (XR) instances with their composition revision and whether they lag behind the latest one</div>
+                </div>
+            </div>
+
+            <div class="section">
+                <h2>Usages</h2>
+
+                <div class="endpoint">
+                    <span class="method">GET</span>
+                    <span class="path"><a href="/api/v1/usages" target="_blank">/api/v1/usages</a></span>
+                    <div class="description">List all Usages and ClusterUsages, with their <code>of</code> and <code>by</code> resources resolved (found, ready, deleting)</div>
+                </div>
+
+                <div class="endpoint">
+                    <span class="method">GET</span>
+                    <span class="path">/api/v1/resources/:kind/:namespace/:name</span>
+                    <div class="description">Status, finalizers and protection of any object: the Usages protecting it and those it is the user of. For composite resources and claims, how their Composition was selected</div>
+                </div>
+            </div>
+
+            <div class="section">
+                <h2>Raw Export</h2>
+
+                <div class="endpoint">
+                    <span class="method">GET</span>
+                    <span class="path">/api/v1/resources/:kind/:namespace/:name/raw</span>
+                    <div class="description">Raw manifest of any object (<code>?format=yaml|json</code>, <code>?strip=managedFields,status,metadata|none</code>), use <code>_</code> as namespace for cluster-scoped objects</div>
+                </div>
+            </div>
+
+            <div class="section">
+                <h2>History</h2>
+
+                <div class="endpoint">
+                    <span class="method">GET</span>
+                    <span class="path">/api/v1/resources/:kind/:namespace/:name/history</span>
+                    <div class="description">Condition transitions observed on an object and the Events about it, oldest first (<code>?type=Ready</code> to filter by condition type)</div>
+                </div>
+            </div>
+
+            <div class="section">
+                <h2>Snapshots</h2>
+
+                <div class="endpoint">
+                    <span class="method">GET</span>
+                    <span class="path"><a href="/api/v1/snapshots" target="_blank">/api/v1/snapshots</a></span>
+                    <div class="description">List snapshots</div>
+                </div>
+
+                <div class="endpoint">
+                    <span class="method">POST</span>
+                    <span class="path">/api/v1/snapshots</span>
+                    <div class="description">Capture a snapshot of all Crossplane objects (status, conditions and spec)</div>
+                </div>
+
+                <div class="endpoint">
+                    <span class="method">GET</span>
+                    <span class="path">/api/v1/snapshots/:id</span>
+                    <div class="description">Get a snapshot with its objects</div>
+                </div>
+
+                <div class="endpoint">
+                    <span class="method">DELETE</span>
+                    <span class="path">/api/v1/snapshots/:id</span>
+                    <div class="description">Delete a snapshot</div>
+                </div>
+
+                <div class="endpoint">
+                    <span class="method">GET</span>
+                    <span class="path">/api/v1/snapshots/diff?from=&amp;to=</span>
+                    <div class="description">Objects added, removed and changed (spec fields and conditions) between two snapshots, <code>to</code> defaults to <code>live</code></div>
+                </div>
+            </div>
+
+            <div class="section">
+                <h2>Reports</h2>
+
+                <div class="endpoint">
+                    <span class="method">GET</span>
+                    <span class="path"><a href="/api/v1/reports/sync-failures" target="_blank">/api/v1/reports/sync-failures</a></span>
+                    <div class="description">Managed resources with <code>Synced=False</code> grouped by provider, kind and normalized error, and drifting resources (<code>?provider=</code>, <code>?kind=</code>, <code>?minUpdates=</code>)</div>
+                </div>
+
+                <div class="endpoint">
+                    <span class="method">GET</span>
+                    <span class="path"><a href="/api/v1/reports/stuck-deletions" target="_blank">/api/v1/reports/stuck-deletions</a></span>
+                    <div class="description">Objects with a deletion timestamp, their remaining finalizers and the Usages blocking them (<code>?minAge=10m</code>, <code>?category=</code>)</div>
+                </div>
+
+                <div class="endpoint">
+                    <span class="method">GET</span>
+                    <span class="path"><a href="/api/v1/reports/orphans" target="_blank">/api/v1/reports/orphans</a></span>
+                    <div class="description">Managed resources whose owning XR is missing or with no owner at all, and XRs whose <code>resourceRefs</code> point to missing objects</div>
+                </div>
+
+                <div class="endpoint">
+                    <span class="method">GET</span>
+                    <span class="path"><a href="/api/v1/reports/paused" target="_blank">/api/v1/reports/paused</a></span>
+                    <div class="description">Objects paused with <code>crossplane.io/paused</code>, with who paused them and when from managedFields (<code>?minAge=24h</code>, <code>?category=</code>)</div>
+                </div>
+
+                <div class="endpoint">
+                    <span class="method">GET</span>
+                    <span class="path"><a href="/api/v1/reports/policies" target="_blank">/api/v1/reports/policies</a></span>
+                    <div class="description">Managed resources per provider, namespace, management and deletion policies, with risky combinations flagged (<code>?provider=</code>, <code>?namespace=</code>, <code>?protectedSelector=protected=true</code>)</div>
+                </div>
+
+                <div class="endpoint">
+                    <span class="method">GET</span>
+                    <span class="path"><a href="/api/v1/reports/v2-upgrade" target="_blank">/api/v1/reports/v2-upgrade</a></span>
+                    <div class="description">Crossplane v1 constructs deprecated or removed in Crossplane v2 (ControllerConfigs, native patch and transform, claims, cluster-scoped XRs...), grouped by object with the changes required before upgrading</div>
+                </div>
+            </div>
+
+            <div class="section">
+                <h2>Search</h2>
+
+                <div class="endpoint">
+                    <span class="method">GET</span>
+                    <span class="path"><a href="/api/v1/search?q=ready" target="_blank">/api/v1/search?q=</a></span>
+                    <div class="description">Search names, labels, annotations, external names, condition messages and spec fields of all Crossplane objects</div>
                 </div>
             </div>
 
@@ -0,0 +1,238 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gravitek/crossplane-spy/internal/k8s"
+	"github.com/gravitek/crossplane-spy/internal/models"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// environmentConfigResource is resolved through discovery, EnvironmentConfigs are served
+// in v1alpha1 before Crossplane v1.18 and in v1beta1 since
+const environmentConfigResource = "environmentconfigs.apiextensions.crossplane.io"
+
+// getEnvironmentConfigs returns all EnvironmentConfigs with their data keys
+func getEnvironmentConfigs(client k8s.ResourceReader) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := context.Background()
+
+		filter, page, err := parseListQuery(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		items, err := listEnvironmentConfigs(ctx, client, filter.ListOptions())
+		if err != nil {
+			log.Printf("Error listing environment configs: %v", err)
+			c.JSON(listErrorStatus(err), gin.H{"error": "Failed to list environment configs"})
+			return
+		}
+
+		configs, next := paginate(filterResources(convertToEnvironmentConfigs(items), filter), page)
+		c.JSON(http.StatusOK, listResponse("EnvironmentConfigList", configs, len(configs), next))
+	}
+}
+
+// getEnvironmentConfig returns an EnvironmentConfig with its data
+func getEnvironmentConfig(client k8s.ResourceReader) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := context.Background()
+		name := c.Param("name")
+
+		gvr, _, err := client.ResolveResource(ctx, environmentConfigResource)
+		if err != nil {
+			if meta.IsNoMatchError(err) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "EnvironmentConfigs are not served by this cluster"})
+				return
+			}
+			log.Printf("Error resolving environment configs: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get environment config"})
+			return
+		}
+
+		obj, err := client.GetResource(ctx, gvr, "", name)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("EnvironmentConfig %q not found", name)})
+				return
+			}
+			log.Printf("Error getting environment config %s: %v", name, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get environment config"})
+			return
+		}
+
+		config := models.ConvertToEnvironmentConfig(obj)
+		config.Data, _, _ = unstructured.NestedMap(obj.Object, "data")
+		c.JSON(http.StatusOK, config)
+	}
+}
+
+// getComposition returns a Composition with its EnvironmentConfig selectors resolved
+// to the EnvironmentConfigs they currently match
+func getComposition(client k8s.ResourceReader) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := context.Background()
+		name := c.Param("name")
+
+		obj, err := client.GetResource(ctx, k8s.CompositionGVR, "", name)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Composition %q not found", name)})
+				return
+			}
+			log.Printf("Error getting composition %s: %v", name, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get composition"})
+			return
+		}
+
+		detail := models.CompositionDetail{
+			Composition: convertToCompositions([]unstructured.Unstructured{*obj})[0],
+			Environment: models.ConvertEnvironmentSelectors(obj),
+		}
+		if len(detail.Environment) > 0 {
+			configs, err := listEnvironmentConfigs(ctx, client, metav1.ListOptions{})
+			if err != nil {
+				log.Printf("Error listing environment configs: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list environment configs"})
+				return
+			}
+			resolveEnvironmentSelectors(detail.Environment, configs)
+		}
+
+		c.JSON(http.StatusOK, detail)
+	}
+}
+
+func convertToEnvironmentConfigs(items []unstructured.Unstructured) []models.EnvironmentConfig {
+	configs := make([]models.EnvironmentConfig, 0, len(items))
+	for i := range items {
+		configs = append(configs, models.ConvertToEnvironmentConfig(&items[i]))
+	}
+	return configs
+}
+
+// listEnvironmentConfigs lists the EnvironmentConfigs in their preferred version
+// Clusters that do not serve them have none
+func listEnvironmentConfigs(ctx context.Context, client k8s.ResourceReader, opts metav1.ListOptions) ([]unstructured.Unstructured, error) {
+	gvr, _, err := client.ResolveResource(ctx, environmentConfigResource)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	list, err := client.ListResources(ctx, gvr, "", opts)
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// resolveEnvironmentSelectors sets the EnvironmentConfigs matched by each selector, as Crossplane would select them
+// Label values taken from the composite resource are unknown here, such selectors list every candidate
+// having the selected label keys instead
+func resolveEnvironmentSelectors(selectors []models.EnvironmentSelector, configs []unstructured.Unstructured) {
+	for i := range selectors {
+		s := &selectors[i]
+
+		if s.Type == models.EnvironmentSourceReference {
+			for _, config := range configs {
+				if config.GetName() == s.Ref {
+					s.Matches = append(s.Matches, s.Ref)
+				}
+			}
+			if len(s.Matches) == 0 {
+				s.Problem = fmt.Sprintf("EnvironmentConfig %q not found", s.Ref)
+			}
+			continue
+		}
+
+		for _, l := range s.MatchLabels {
+			s.Dynamic = s.Dynamic || l.Type != models.EnvironmentLabelValue
+		}
+		var matched []unstructured.Unstructured
+		for _, config := range configs {
+			if matchEnvironmentLabels(config.GetLabels(), s.MatchLabels) {
+				matched = append(matched, config)
+			}
+		}
+		sortErr := sortEnvironmentConfigs(matched, s.SortByFieldPath)
+
+		if !s.Dynamic && s.Mode == models.EnvironmentSelectorMultiple && s.MaxMatch != nil && len(matched) > *s.MaxMatch {
+			matched = matched[:*s.MaxMatch]
+		}
+		for _, config := range matched {
+			s.Matches = append(s.Matches, config.GetName())
+		}
+
+		// Crossplane fails the composition in these cases, the matches are kept to show why
+		switch {
+		case sortErr != nil:
+			s.Problem = sortErr.Error()
+		case s.Mode == models.EnvironmentSelectorSingle && len(s.Matches) == 0:
+			s.Problem = "No EnvironmentConfig matches the selector"
+		case !s.Dynamic && s.Mode == models.EnvironmentSelectorSingle && len(s.Matches) > 1:
+			s.Problem = fmt.Sprintf("Single mode selector matches %d EnvironmentConfigs, Crossplane requires exactly one", len(s.Matches))
+		case !s.Dynamic && s.MinMatch != nil && len(s.Matches) < *s.MinMatch:
+			s.Problem = fmt.Sprintf("The selector matches %d EnvironmentConfigs, minMatch is %d", len(s.Matches), *s.MinMatch)
+		}
+	}
+}
+
+// matchEnvironmentLabels reports whether labels match a selector
+// Only the presence of the keys whose values are taken from the composite resource is checked
+func matchEnvironmentLabels(labels map[string]string, selector []models.EnvironmentLabel) bool {
+	for _, l := range selector {
+		value, ok := labels[l.Key]
+		if !ok || (l.Type == models.EnvironmentLabelValue && value != l.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// sortEnvironmentConfigs sorts EnvironmentConfigs by the value of a field path, numbers numerically
+// As in Crossplane, every EnvironmentConfig must have a value at the field path
+func sortEnvironmentConfigs(configs []unstructured.Unstructured, fieldPath string) error {
+	fields := strings.Split(fieldPath, ".")
+	for _, config := range configs {
+		if value, found, _ := unstructured.NestedFieldNoCopy(config.Object, fields...); !found || value == nil {
+			return fmt.Errorf("EnvironmentConfig %q has no value at sortByFieldPath %s", config.GetName(), fieldPath)
+		}
+	}
+
+	sort.SliceStable(configs, func(i, j int) bool {
+		a, _, _ := unstructured.NestedFieldNoCopy(configs[i].Object, fields...)
+		b, _, _ := unstructured.NestedFieldNoCopy(configs[j].Object, fields...)
+
+		if x, ok := toFloat(a); ok {
+			if y, ok := toFloat(b); ok {
+				return x < y
+			}
+		}
+		return fmt.Sprint(a) < fmt.Sprint(b)
+	})
+	return nil
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
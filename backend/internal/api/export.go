@@ -0,0 +1,142 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gravitek/crossplane-spy/internal/k8s"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// Parts of an object that can be stripped from an export with ?strip=
+const (
+	stripManagedFields = "managedFields"
+	stripStatus        = "status"
+	stripMetadata      = "metadata"
+	stripNone          = "none"
+)
+
+// serverPopulatedMetadata are the metadata fields set by the API server or tied to objects of the source
+// cluster, they must be removed for the manifest to be re-applied to another cluster
+var serverPopulatedMetadata = []string{
+	"uid",
+	"resourceVersion",
+	"generation",
+	"creationTimestamp",
+	"deletionTimestamp",
+	"deletionGracePeriodSeconds",
+	"selfLink",
+	"managedFields",
+	"ownerReferences",
+}
+
+// lastAppliedAnnotation holds the manifest last applied by kubectl, stripped with the server-populated metadata
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// exportResource returns the raw manifest of any object as JSON or YAML
+// managedFields are stripped by default, ?strip= selects what to remove
+func exportResource(client k8s.ResourceReader) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		strip, err := parseStrip(c.DefaultQuery("strip", stripManagedFields))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		format, err := exportFormat(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		obj, _, ok := fetchObject(ctx, c, client)
+		if !ok {
+			return
+		}
+
+		stripObject(obj, strip)
+
+		if format == "yaml" {
+			data, err := yaml.Marshal(obj.Object)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encode resource as YAML"})
+				return
+			}
+			c.Data(http.StatusOK, "application/yaml; charset=utf-8", data)
+			return
+		}
+
+		data, err := json.MarshalIndent(obj.Object, "", "  ")
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encode resource as JSON"})
+			return
+		}
+		c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+	}
+}
+
+// parseStrip parses the comma-separated list of parts to strip
+func parseStrip(raw string) (map[string]bool, error) {
+	strip := make(map[string]bool)
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		switch part {
+		case "", stripNone:
+		case stripManagedFields, stripStatus, stripMetadata:
+			strip[part] = true
+		default:
+			return nil, fmt.Errorf("invalid strip value %q: must be managedFields, status, metadata or none", part)
+		}
+	}
+	return strip, nil
+}
+
+// exportFormat negotiates the output format, ?format= takes precedence over the Accept header
+func exportFormat(c *gin.Context) (string, error) {
+	switch strings.ToLower(c.Query("format")) {
+	case "yaml", "yml":
+		return "yaml", nil
+	case "json":
+		return "json", nil
+	case "":
+	default:
+		return "", fmt.Errorf("invalid format: must be json or yaml")
+	}
+
+	switch c.NegotiateFormat("application/json", "application/yaml", "application/x-yaml", "text/yaml") {
+	case "application/yaml", "application/x-yaml", "text/yaml":
+		return "yaml", nil
+	default:
+		return "json", nil
+	}
+}
+
+// stripObject removes the selected parts from an object
+func stripObject(obj *unstructured.Unstructured, strip map[string]bool) {
+	if strip[stripManagedFields] {
+		unstructured.RemoveNestedField(obj.Object, "metadata", "managedFields")
+	}
+
+	if strip[stripStatus] {
+		unstructured.RemoveNestedField(obj.Object, "status")
+	}
+
+	if strip[stripMetadata] {
+		for _, field := range serverPopulatedMetadata {
+			unstructured.RemoveNestedField(obj.Object, "metadata", field)
+		}
+		if annotations := obj.GetAnnotations(); annotations != nil {
+			delete(annotations, lastAppliedAnnotation)
+			if len(annotations) == 0 {
+				annotations = nil
+			}
+			obj.SetAnnotations(annotations)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gravitek/crossplane-spy/internal/models"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// listFilter holds the filtering query parameters accepted by every list endpoint
+// Selectors are passed down to the API server, the other criteria are applied in-process
+type listFilter struct {
+	LabelSelector string
+	FieldSelector string
+	Namespace     string
+	Kind          string
+	Ready         *bool
+	Synced        *bool
+
+	// name matches a substring, or a regular expression when written as /pattern/
+	name      string
+	nameRegex *regexp.Regexp
+}
+
+// parseListFilter reads and validates the filtering query parameters
+func parseListFilter(c *gin.Context) (listFilter, error) {
+	f := listFilter{
+		LabelSelector: c.Query("labelSelector"),
+		FieldSelector: c.Query("fieldSelector"),
+		Namespace:     c.Query("namespace"),
+		Kind:          c.Query("kind"),
+	}
+
+	if f.LabelSelector != "" {
+		if _, err := labels.Parse(f.LabelSelector); err != nil {
+			return f, fmt.Errorf("invalid labelSelector: %w", err)
+		}
+	}
+
+	if f.FieldSelector != "" {
+		if _, err := fields.ParseSelector(f.FieldSelector); err != nil {
+			return f, fmt.Errorf("invalid fieldSelector: %w", err)
+		}
+	}
+
+	var err error
+	if f.Ready, err = parseBoolQuery(c, "ready"); err != nil {
+		return f, err
+	}
+	if f.Synced, err = parseBoolQuery(c, "synced"); err != nil {
+		return f, err
+	}
+
+	if name := c.Query("name"); len(name) > 2 && strings.HasPrefix(name, "/") && strings.HasSuffix(name, "/") {
+		if f.nameRegex, err = regexp.Compile(name[1 : len(name)-1]); err != nil {
+			return f, fmt.Errorf("invalid name pattern: %w", err)
+		}
+	} else {
+		f.name = name
+	}
+
+	return f, nil
+}
+
+// parseBoolQuery parses an optional boolean query parameter
+func parseBoolQuery(c *gin.Context, key string) (*bool, error) {
+	raw, ok := c.GetQuery(key)
+	if !ok || raw == "" {
+		return nil, nil
+	}
+
+	b, err := strconv.ParseBool(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: must be true or false", key)
+	}
+	return &b, nil
+}
+
+// ListOptions returns the criteria the API server can apply itself
+func (f listFilter) ListOptions() metav1.ListOptions {
+	return metav1.ListOptions{
+		LabelSelector: f.LabelSelector,
+		FieldSelector: f.FieldSelector,
+	}
+}
+
+// inProcess reports whether any criteria must be applied after listing
+func (f listFilter) inProcess() bool {
+	return f.Namespace != "" || f.Kind != "" || f.name != "" || f.nameRegex != nil || f.Ready != nil || f.Synced != nil
+}
+
+// Matches applies the in-process criteria to a converted resource
+func (f listFilter) Matches(r models.Resource) bool {
+	base := r.GetBaseResource()
+
+	if f.Namespace != "" && base.Metadata.Namespace != f.Namespace {
+		return false
+	}
+
+	if f.Kind != "" && !strings.EqualFold(base.Kind, f.Kind) {
+		return false
+	}
+
+	if f.name != "" && !strings.Contains(base.Metadata.Name, f.name) {
+		return false
+	}
+
+	if f.nameRegex != nil && !f.nameRegex.MatchString(base.Metadata.Name) {
+		return false
+	}
+
+	status := r.GetResourceStatus()
+
+	if f.Ready != nil && status.Ready != *f.Ready {
+		return false
+	}
+
+	if f.Synced != nil && models.IsConditionTrue(status.Conditions, "Synced") != *f.Synced {
+		return false
+	}
+
+	return true
+}
+
+// filterResources keeps the resources matching the in-process criteria
+func filterResources[T models.Resource](items []T, f listFilter) []T {
+	filtered := make([]T, 0, len(items))
+	for _, item := range items {
+		if f.Matches(item) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
@@ -2,31 +2,37 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gravitek/crossplane-spy/internal/k8s"
 	"github.com/gravitek/crossplane-spy/internal/models"
+	"github.com/gravitek/crossplane-spy/internal/report"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 // getResources returns all Crossplane resources summary
-func getResources(client *k8s.Client) gin.HandlerFunc {
+func getResources(client k8s.ResourceReader) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx := context.Background()
 
 		// Get all resource types
-		providers, _ := client.ListProviders(ctx)
-		xrds, _ := client.ListXRDs(ctx)
-		compositions, _ := client.ListCompositions(ctx)
-		functions, _ := client.ListFunctions(ctx)
+		providers, _ := client.ListProviders(ctx, metav1.ListOptions{})
+		xrds, _ := client.ListXRDs(ctx, metav1.ListOptions{})
+		compositions, _ := client.ListCompositions(ctx, metav1.ListOptions{})
+		functions, _ := client.ListFunctions(ctx, metav1.ListOptions{})
 
 		// Get ProviderConfigs count
 		providerConfigsCount := 0
 		if gvrs, err := client.DiscoverProviderConfigGVRs(ctx); err == nil {
 			for _, gvr := range gvrs {
-				if configs, err := client.ListProviderConfigs(ctx, gvr); err == nil {
+				if configs, err := client.ListProviderConfigs(ctx, gvr, metav1.ListOptions{}); err == nil {
 					providerConfigsCount += len(configs.Items)
 				}
 			}
@@ -36,7 +42,7 @@ func getResources(client *k8s.Client) gin.HandlerFunc {
 		compositeResourcesCount := 0
 		if gvrs, err := client.DiscoverXRDGVRs(ctx); err == nil {
 			for _, gvr := range gvrs {
-				if xrs, err := client.ListXRs(ctx, gvr, ""); err == nil {
+				if xrs, err := client.ListXRs(ctx, gvr, "", metav1.ListOptions{}); err == nil {
 					compositeResourcesCount += len(xrs.Items)
 				}
 			}
@@ -56,7 +62,7 @@ func getResources(client *k8s.Client) gin.HandlerFunc {
 }
 
 // getResourcesByKind returns resources of a specific kind
-func getResourcesByKind(client *k8s.Client) gin.HandlerFunc {
+func getResourcesByKind(client k8s.ResourceReader) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		kind := c.Param("kind")
 		c.JSON(http.StatusOK, gin.H{
@@ -67,45 +73,84 @@ func getResourcesByKind(client *k8s.Client) gin.HandlerFunc {
 }
 
 // getResource returns a specific resource
-func getResource(client *k8s.Client) gin.HandlerFunc {
+func getResource(client k8s.ResourceReader, objectCache *k8s.ObjectCache) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		kind := c.Param("kind")
-		namespace := c.Param("namespace")
-		name := c.Param("name")
-		c.JSON(http.StatusOK, gin.H{
-			"kind":      kind,
-			"namespace": namespace,
-			"name":      name,
-		})
+		ctx := context.Background()
+
+		obj, _, ok := fetchObject(ctx, c, client)
+		if !ok {
+			return
+		}
+
+		scope := models.ScopeCluster
+		if obj.GetNamespace() != "" {
+			scope = models.ScopeNamespace
+		}
+		status := models.ConvertToResourceStatus(obj)
+		detail := models.ResourceDetail{
+			BaseResource: models.ConvertToBaseResource(obj, scope),
+			Status:       status,
+			Finalizers:   obj.GetFinalizers(),
+			Protection:   protectionOf(ctx, client, objectCache, obj),
+			Paused:       models.IsPaused(obj, status.Conditions),
+		}
+		detail.ManagementPolicies, detail.DeletionPolicy = models.ConvertManagedPolicies(obj)
+		for _, cond := range status.Conditions {
+			if cond.Type == "Synced" && cond.Reason == models.ReasonReconcilePaused {
+				detail.PausedReason = cond.Reason
+			}
+		}
+		if deletion := obj.GetDeletionTimestamp(); deletion != nil {
+			detail.DeletionTimestamp = &deletion.Time
+		}
+		if selection, ok := report.CompositionSelection(obj, objectCache.Objects(k8s.CategoryXRD), objectCache.Objects(k8s.CategoryComposition)); ok {
+			detail.CompositionSelection = &selection
+		}
+
+		c.JSON(http.StatusOK, detail)
 	}
 }
 
 // getProviders returns all Provider resources
-func getProviders(client *k8s.Client) gin.HandlerFunc {
+func getProviders(client k8s.ResourceReader) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx := context.Background()
 
-		providerList, err := client.ListProviders(ctx)
+		filter, page, err := parseListQuery(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		opts, live, err := page.listOptions(filter)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		providerList, err := client.ListProviders(ctx, opts)
 		if err != nil {
 			log.Printf("Error listing providers: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list providers"})
+			c.JSON(listErrorStatus(err), gin.H{"error": "Failed to list providers"})
 			return
 		}
 
-		providers := convertToProviders(providerList.Items)
-		c.JSON(http.StatusOK, gin.H{
-			"kind":  "ProviderList",
-			"count": len(providers),
-			"items": providers,
-		})
+		providers, next := pageList(filterResources(convertToProviders(providerList.Items), filter), providerList, page, live)
+		c.JSON(http.StatusOK, listResponse("ProviderList", providers, len(providers), next))
 	}
 }
 
 // getProviderConfigs returns all ProviderConfig resources
-func getProviderConfigs(client *k8s.Client) gin.HandlerFunc {
+func getProviderConfigs(client k8s.ResourceReader) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx := context.Background()
 
+		filter, page, err := parseListQuery(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
 		gvrs, err := client.DiscoverProviderConfigGVRs(ctx)
 		if err != nil {
 			log.Printf("Error discovering provider configs: %v", err)
@@ -115,69 +160,93 @@ func getProviderConfigs(client *k8s.Client) gin.HandlerFunc {
 
 		var allConfigs []models.ProviderConfig
 		for _, gvr := range gvrs {
-			configs, err := client.ListProviderConfigs(ctx, gvr)
+			configs, err := client.ListProviderConfigs(ctx, gvr, filter.ListOptions())
 			if err != nil {
 				log.Printf("Error listing provider configs for %v: %v", gvr, err)
+				if status := listErrorStatus(err); status != http.StatusInternalServerError {
+					c.JSON(status, gin.H{"error": "Failed to list provider configs"})
+					return
+				}
 				continue
 			}
-			allConfigs = append(allConfigs, convertToProviderConfigs(configs.Items)...)
+			allConfigs = append(allConfigs, filterResources(convertToProviderConfigs(configs.Items), filter)...)
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"kind":  "ProviderConfigList",
-			"count": len(allConfigs),
-			"items": allConfigs,
-		})
+		allConfigs, next := paginate(allConfigs, page)
+		attachProviderConfigUsages(ctx, client, allConfigs)
+		c.JSON(http.StatusOK, listResponse("ProviderConfigList", allConfigs, len(allConfigs), next))
 	}
 }
 
 // getXRDs returns all XRD resources
-func getXRDs(client *k8s.Client) gin.HandlerFunc {
+func getXRDs(client k8s.ResourceReader) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx := context.Background()
 
-		xrdList, err := client.ListXRDs(ctx)
+		filter, page, err := parseListQuery(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		opts, live, err := page.listOptions(filter)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		xrdList, err := client.ListXRDs(ctx, opts)
 		if err != nil {
 			log.Printf("Error listing XRDs: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list XRDs"})
+			c.JSON(listErrorStatus(err), gin.H{"error": "Failed to list XRDs"})
 			return
 		}
 
-		xrds := convertToXRDs(xrdList.Items)
-		c.JSON(http.StatusOK, gin.H{
-			"kind":  "CompositeResourceDefinitionList",
-			"count": len(xrds),
-			"items": xrds,
-		})
+		xrds, next := pageList(filterResources(convertToXRDs(xrdList.Items), filter), xrdList, page, live)
+		c.JSON(http.StatusOK, listResponse("CompositeResourceDefinitionList", xrds, len(xrds), next))
 	}
 }
 
 // getCompositions returns all Composition resources
-func getCompositions(client *k8s.Client) gin.HandlerFunc {
+func getCompositions(client k8s.ResourceReader) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx := context.Background()
 
-		compList, err := client.ListCompositions(ctx)
+		filter, page, err := parseListQuery(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		opts, live, err := page.listOptions(filter)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		compList, err := client.ListCompositions(ctx, opts)
 		if err != nil {
 			log.Printf("Error listing compositions: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list compositions"})
+			c.JSON(listErrorStatus(err), gin.H{"error": "Failed to list compositions"})
 			return
 		}
 
-		compositions := convertToCompositions(compList.Items)
-		c.JSON(http.StatusOK, gin.H{
-			"kind":  "CompositionList",
-			"count": len(compositions),
-			"items": compositions,
-		})
+		compositions, next := pageList(filterResources(convertToCompositions(compList.Items), filter), compList, page, live)
+		c.JSON(http.StatusOK, listResponse("CompositionList", compositions, len(compositions), next))
 	}
 }
 
 // getXRs returns all Composite Resource (XR) instances
-func getXRs(client *k8s.Client) gin.HandlerFunc {
+func getXRs(client k8s.ResourceReader) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx := context.Background()
 
+		filter, page, err := parseListQuery(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
 		gvrs, err := client.DiscoverXRDGVRs(ctx)
 		if err != nil {
 			log.Printf("Error discovering XRs: %v", err)
@@ -187,72 +256,112 @@ func getXRs(client *k8s.Client) gin.HandlerFunc {
 
 		var allXRs []models.CompositeResource
 		for _, gvr := range gvrs {
-			xrs, err := client.ListXRs(ctx, gvr, "")
+			namespace, ok := listNamespace(ctx, client, gvr, filter)
+			if !ok {
+				continue
+			}
+			xrs, err := client.ListXRs(ctx, gvr, namespace, filter.ListOptions())
 			if err != nil {
 				log.Printf("Error listing XRs for %v: %v", gvr, err)
+				if status := listErrorStatus(err); status != http.StatusInternalServerError {
+					c.JSON(status, gin.H{"error": "Failed to list composite resources"})
+					return
+				}
 				continue
 			}
-			allXRs = append(allXRs, convertToCompositeResources(xrs.Items)...)
+			allXRs = append(allXRs, filterResources(convertToCompositeResources(xrs.Items), filter)...)
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"kind":  "CompositeResourceList",
-			"count": len(allXRs),
-			"items": allXRs,
-		})
+		allXRs, next := paginate(allXRs, page)
+		markRevisionLag(ctx, client, allXRs)
+		c.JSON(http.StatusOK, listResponse("CompositeResourceList", allXRs, len(allXRs), next))
 	}
 }
 
 // getFunctions returns all Function resources
-func getFunctions(client *k8s.Client) gin.HandlerFunc {
+func getFunctions(client k8s.ResourceReader) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx := context.Background()
 
-		funcList, err := client.ListFunctions(ctx)
+		filter, page, err := parseListQuery(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		opts, live, err := page.listOptions(filter)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		funcList, err := client.ListFunctions(ctx, opts)
 		if err != nil {
 			log.Printf("Error listing functions: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list functions"})
+			c.JSON(listErrorStatus(err), gin.H{"error": "Failed to list functions"})
 			return
 		}
 
-		functions := convertToFunctions(funcList.Items)
-		c.JSON(http.StatusOK, gin.H{
-			"kind":  "FunctionList",
-			"count": len(functions),
-			"items": functions,
-		})
+		functions, next := pageList(filterResources(convertToFunctions(funcList.Items), filter), funcList, page, live)
+		c.JSON(http.StatusOK, listResponse("FunctionList", functions, len(functions), next))
 	}
 }
 
 // getClusterResources returns cluster-scoped Crossplane resources
-func getClusterResources(client *k8s.Client) gin.HandlerFunc {
+func getClusterResources(client k8s.ResourceReader) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx := context.Background()
 
-		providers, _ := client.ListProviders(ctx)
-		xrds, _ := client.ListXRDs(ctx)
-		compositions, _ := client.ListCompositions(ctx)
-		functions, _ := client.ListFunctions(ctx)
+		filter, page, err := parseListQuery(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 
-		var resources []interface{}
-		resources = append(resources, convertToAnySlice(convertToProviders(providers.Items))...)
-		resources = append(resources, convertToAnySlice(convertToXRDs(xrds.Items))...)
-		resources = append(resources, convertToAnySlice(convertToCompositions(compositions.Items))...)
-		resources = append(resources, convertToAnySlice(convertToFunctions(functions.Items))...)
+		opts := filter.ListOptions()
+		providers, _ := client.ListProviders(ctx, opts)
+		xrds, _ := client.ListXRDs(ctx, opts)
+		compositions, _ := client.ListCompositions(ctx, opts)
+		functions, _ := client.ListFunctions(ctx, opts)
 
-		c.JSON(http.StatusOK, gin.H{
+		var resources []models.Resource
+		if providers != nil {
+			resources = append(resources, convertToResourceSlice(filterResources(convertToProviders(providers.Items), filter))...)
+		}
+		if xrds != nil {
+			resources = append(resources, convertToResourceSlice(filterResources(convertToXRDs(xrds.Items), filter))...)
+		}
+		if compositions != nil {
+			resources = append(resources, convertToResourceSlice(filterResources(convertToCompositions(compositions.Items), filter))...)
+		}
+		if functions != nil {
+			resources = append(resources, convertToResourceSlice(filterResources(convertToFunctions(functions.Items), filter))...)
+		}
+
+		resources, next := paginate(resources, page)
+		response := gin.H{
 			"scope": "cluster",
 			"count": len(resources),
 			"items": resources,
-		})
+		}
+		if next != "" {
+			response["continue"] = next
+		}
+		c.JSON(http.StatusOK, response)
 	}
 }
 
 // getNamespaceResources returns namespace-scoped Crossplane resources
-func getNamespaceResources(client *k8s.Client) gin.HandlerFunc {
+func getNamespaceResources(client k8s.ResourceReader) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx := context.Background()
 
+		filter, page, err := parseListQuery(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
 		gvrs, err := client.DiscoverXRDGVRs(ctx)
 		if err != nil {
 			log.Printf("Error discovering namespace resources: %v", err)
@@ -260,165 +369,184 @@ func getNamespaceResources(client *k8s.Client) gin.HandlerFunc {
 			return
 		}
 
-		var resources []interface{}
+		var resources []models.Resource
 		for _, gvr := range gvrs {
 			// Try to list with namespace (will fail for cluster-scoped)
 			namespaces := []string{"default", "crossplane-system"}
+			if filter.Namespace != "" {
+				namespaces = []string{filter.Namespace}
+			}
 			for _, ns := range namespaces {
-				xrs, err := client.ListXRs(ctx, gvr, ns)
+				xrs, err := client.ListXRs(ctx, gvr, ns, filter.ListOptions())
 				if err != nil {
+					if status := listErrorStatus(err); status != http.StatusInternalServerError {
+						c.JSON(status, gin.H{"error": "Failed to list namespace resources"})
+						return
+					}
 					continue
 				}
-				resources = append(resources, convertToAnySlice(convertToCompositeResources(xrs.Items))...)
+				resources = append(resources, convertToResourceSlice(filterResources(convertToCompositeResources(xrs.Items), filter))...)
 			}
 		}
 
-		c.JSON(http.StatusOK, gin.H{
+		resources, next := paginate(resources, page)
+		response := gin.H{
 			"scope": "namespace",
 			"count": len(resources),
 			"items": resources,
-		})
+		}
+		if next != "" {
+			response["continue"] = next
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// clusterScopeNamespace is the namespace path segment used for cluster-scoped objects
+// e.g. /api/v1/resources/providers/_/provider-aws
+const clusterScopeNamespace = "_"
+
+// resolveKind resolves the :kind and :namespace path parameters to a GVR and a namespace
+// The namespace is empty for cluster-scoped kinds
+// On failure the error response is written and false is returned
+func resolveKind(ctx context.Context, c *gin.Context, client k8s.ResourceReader) (schema.GroupVersionResource, string, bool) {
+	kind := c.Param("kind")
+	namespace := c.Param("namespace")
+
+	gvr, clusterScoped, err := client.ResolveResource(ctx, kind)
+	if err != nil {
+		if meta.IsAmbiguousError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("ambiguous kind %q, qualify it with its group (e.g. buckets.s3.aws.upbound.io)", kind)})
+			return gvr, "", false
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("unknown kind %q", kind)})
+		return gvr, "", false
+	}
+
+	if clusterScoped || namespace == clusterScopeNamespace {
+		namespace = ""
 	}
+	return gvr, namespace, true
+}
+
+// fetchObject resolves the :kind, :namespace and :name path parameters and gets the live object
+// On failure the error response is written and false is returned
+func fetchObject(ctx context.Context, c *gin.Context, client k8s.ResourceReader) (*unstructured.Unstructured, schema.GroupVersionResource, bool) {
+	kind := c.Param("kind")
+	name := c.Param("name")
+
+	gvr, namespace, ok := resolveKind(ctx, c, client)
+	if !ok {
+		return nil, gvr, false
+	}
+
+	obj, err := client.GetResource(ctx, gvr, namespace, name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("%s %q not found", kind, name)})
+			return nil, gvr, false
+		}
+		log.Printf("Error getting %v %s/%s: %v", gvr, namespace, name, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get resource"})
+		return nil, gvr, false
+	}
+
+	return obj, gvr, true
+}
+
+// listNamespace returns the namespace to list a resource type in for the given filter
+// Cluster-scoped types are skipped when a namespace filter is set, since they cannot match
+func listNamespace(ctx context.Context, client k8s.ResourceReader, gvr schema.GroupVersionResource, filter listFilter) (string, bool) {
+	if filter.Namespace == "" {
+		return "", true
+	}
+
+	clusterScoped, err := client.IsClusterScoped(ctx, gvr)
+	if err != nil {
+		// Unknown scope, list everything and let the in-process filter decide
+		return "", true
+	}
+	if clusterScoped {
+		return "", false
+	}
+	return filter.Namespace, true
+}
+
+// listErrorStatus maps a list error from the API server to an HTTP status
+// Invalid selectors and expired continue tokens are reported as client errors, handlers listing several kinds
+// fail on them instead of skipping the kind
+func listErrorStatus(err error) int {
+	if apierrors.IsBadRequest(err) || apierrors.IsInvalid(err) {
+		return http.StatusBadRequest
+	}
+	if apierrors.IsResourceExpired(err) {
+		// The continue token is too old, the client has to restart from the first page
+		return http.StatusGone
+	}
+	return http.StatusInternalServerError
 }
 
 // Converter functions
 
 func convertToProviders(items []unstructured.Unstructured) []models.Provider {
 	providers := make([]models.Provider, 0, len(items))
-	for _, item := range items {
-		resourceStatus := models.ConvertToResourceStatus(&item)
-		installed, healthy := models.IsProviderHealthy(resourceStatus.Conditions)
-
-		// A Provider is "Ready" if both Installed and Healthy are true
-		resourceStatus.Ready = installed && healthy
-
-		// Extract package from spec
-		pkg, _, _ := unstructured.NestedString(item.Object, "spec", "package")
-
-		provider := models.Provider{
-			BaseResource: models.ConvertToBaseResource(&item, models.ScopeCluster),
-			Spec: models.ProviderSpec{
-				Package: pkg,
-			},
-			Status: models.ProviderStatus{
-				ResourceStatus:  resourceStatus,
-				Installed:       installed,
-				Healthy:         healthy,
-			},
-		}
-		providers = append(providers, provider)
+	for i := range items {
+		providers = append(providers, models.ConvertToProvider(&items[i]))
 	}
 	return providers
 }
 
 func convertToProviderConfigs(items []unstructured.Unstructured) []models.ProviderConfig {
 	configs := make([]models.ProviderConfig, 0, len(items))
-	for _, item := range items {
-		config := models.ProviderConfig{
-			BaseResource: models.ConvertToBaseResource(&item, models.ScopeCluster),
-			Status:       models.ConvertToResourceStatus(&item),
-		}
-		configs = append(configs, config)
+	for i := range items {
+		configs = append(configs, models.ConvertToProviderConfig(&items[i]))
 	}
 	return configs
 }
 
 func convertToXRDs(items []unstructured.Unstructured) []models.XRD {
 	xrds := make([]models.XRD, 0, len(items))
-	for _, item := range items {
-		resourceStatus := models.ConvertToResourceStatus(&item)
-		established := models.IsXRDEstablished(resourceStatus.Conditions)
-
-		// An XRD is "Ready" if Established is true
-		resourceStatus.Ready = established
-
-		// Extract group from spec
-		group, _, _ := unstructured.NestedString(item.Object, "spec", "group")
-
-		xrd := models.XRD{
-			BaseResource: models.ConvertToBaseResource(&item, models.ScopeCluster),
-			Spec: models.XRDSpec{
-				Group: group,
-			},
-			Status: models.XRDStatus{
-				ResourceStatus: resourceStatus,
-				Established:    established,
-			},
-		}
-		xrds = append(xrds, xrd)
+	for i := range items {
+		xrds = append(xrds, models.ConvertToXRD(&items[i]))
 	}
 	return xrds
 }
 
 func convertToCompositions(items []unstructured.Unstructured) []models.Composition {
 	compositions := make([]models.Composition, 0, len(items))
-	for _, item := range items {
-		// Extract compositeTypeRef.apiVersion from spec
-		apiVersion, _, _ := unstructured.NestedString(item.Object, "spec", "compositeTypeRef", "apiVersion")
-		kind, _, _ := unstructured.NestedString(item.Object, "spec", "compositeTypeRef", "kind")
-
-		composition := models.Composition{
-			BaseResource: models.ConvertToBaseResource(&item, models.ScopeCluster),
-			Spec: models.CompositionSpec{
-				CompositeTypeRef: models.TypeReference{
-					APIVersion: apiVersion,
-					Kind:       kind,
-				},
-			},
-			Status: models.CompositionStatus{ResourceStatus: models.ConvertToResourceStatus(&item)},
-		}
-		compositions = append(compositions, composition)
+	for i := range items {
+		compositions = append(compositions, models.ConvertToComposition(&items[i]))
 	}
 	return compositions
 }
 
 func convertToFunctions(items []unstructured.Unstructured) []models.Function {
 	functions := make([]models.Function, 0, len(items))
-	for _, item := range items {
-		resourceStatus := models.ConvertToResourceStatus(&item)
-		installed, healthy := models.IsFunctionHealthy(resourceStatus.Conditions)
-
-		// A Function is "Ready" if both Installed and Healthy are true
-		resourceStatus.Ready = installed && healthy
-
-		// Extract package from spec
-		pkg, _, _ := unstructured.NestedString(item.Object, "spec", "package")
-
-		function := models.Function{
-			BaseResource: models.ConvertToBaseResource(&item, models.ScopeCluster),
-			Spec: models.FunctionSpec{
-				Package: pkg,
-			},
-			Status: models.FunctionStatus{
-				ResourceStatus: resourceStatus,
-				Installed:      installed,
-				Healthy:        healthy,
-			},
-		}
-		functions = append(functions, function)
+	for i := range items {
+		functions = append(functions, models.ConvertToFunction(&items[i]))
 	}
 	return functions
 }
 
 func convertToCompositeResources(items []unstructured.Unstructured) []models.CompositeResource {
 	xrs := make([]models.CompositeResource, 0, len(items))
-	for _, item := range items {
-		scope := models.ScopeCluster
-		if item.GetNamespace() != "" {
-			scope = models.ScopeNamespace
-		}
-		xr := models.CompositeResource{
-			BaseResource: models.ConvertToBaseResource(&item, scope),
-			Status:       models.CompositeResourceStatus{ResourceStatus: models.ConvertToResourceStatus(&item)},
-		}
-		xrs = append(xrs, xr)
+	for i := range items {
+		xrs = append(xrs, models.ConvertToCompositeResource(&items[i]))
 	}
 	return xrs
 }
 
-// Generic converter to []interface{}
-func convertToAnySlice[T any](items []T) []interface{} {
-	result := make([]interface{}, len(items))
+func convertToUsages(items []unstructured.Unstructured) []models.Usage {
+	usages := make([]models.Usage, 0, len(items))
+	for _, item := range items {
+		usages = append(usages, models.ConvertToUsage(&item))
+	}
+	return usages
+}
+
+// Generic converter to []models.Resource
+func convertToResourceSlice[T models.Resource](items []T) []models.Resource {
+	result := make([]models.Resource, len(items))
 	for i, item := range items {
 		result[i] = item
 	}
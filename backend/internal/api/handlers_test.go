@@ -0,0 +1,1275 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gravitek/crossplane-spy/internal/history"
+	"github.com/gravitek/crossplane-spy/internal/k8s"
+	"github.com/gravitek/crossplane-spy/internal/models"
+	"github.com/gravitek/crossplane-spy/internal/report"
+	"github.com/gravitek/crossplane-spy/internal/snapshot"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// testServer serves the API over fake clients loaded with testdata/cluster.yaml
+type testServer struct {
+	router  *gin.Engine
+	history *history.MemoryStore
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	return newWrappedTestServer(t, nil)
+}
+
+// newWrappedTestServer serves the API through a wrapper of the fake client, e.g. to inject API server errors,
+// the object cache still reads the fake client
+func newWrappedTestServer(t *testing.T, wrap func(k8s.ResourceReader) k8s.ResourceReader) *testServer {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	client, err := k8s.NewFakeClient(loadObjects(t, "testdata/cluster.yaml")...)
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	objectCache := k8s.NewObjectCache(client)
+	objectCache.Start(ctx)
+	deadline := time.Now().Add(5 * time.Second)
+	for !objectCache.HasSynced() {
+		if time.Now().After(deadline) {
+			t.Fatal("object cache did not sync")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	var reader k8s.ResourceReader = client
+	if wrap != nil {
+		reader = wrap(client)
+	}
+
+	historyStore := history.NewMemoryStore(0, 0)
+	return &testServer{
+		router:  NewRouter(reader, objectCache, historyStore, snapshot.NewMemoryStore(0), report.NewChurnTracker(report.DefaultChurnWindow)),
+		history: historyStore,
+	}
+}
+
+// loadObjects reads the objects of a multi-document YAML file
+func loadObjects(t *testing.T, path string) []*unstructured.Unstructured {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open %s: %v", path, err)
+	}
+	defer f.Close()
+
+	var objs []*unstructured.Unstructured
+	decoder := utilyaml.NewYAMLOrJSONDecoder(f, 4096)
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if err == io.EOF {
+				return objs
+			}
+			t.Fatalf("failed to decode %s: %v", path, err)
+		}
+		if obj.Object != nil {
+			objs = append(objs, obj)
+		}
+	}
+}
+
+// do sends a request and returns the response recorder
+func (s *testServer) do(t *testing.T, method, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, nil)
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+// getJSON sends a GET request, checks the status code and decodes the JSON body
+func (s *testServer) getJSON(t *testing.T, path string, wantStatus int) map[string]interface{} {
+	t.Helper()
+	w := s.do(t, http.MethodGet, path)
+	if w.Code != wantStatus {
+		t.Fatalf("GET %s: got status %d, want %d: %s", path, w.Code, wantStatus, w.Body.String())
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("GET %s: invalid JSON: %v", path, err)
+	}
+	return body
+}
+
+// itemNames returns the metadata.name of the items of a list response
+func itemNames(t *testing.T, body map[string]interface{}) []string {
+	t.Helper()
+	items, _ := body["items"].([]interface{})
+
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		name, _, _ := unstructured.NestedString(item.(map[string]interface{}), "metadata", "name")
+		names = append(names, name)
+	}
+	return names
+}
+
+func TestHealthCheck(t *testing.T) {
+	s := newTestServer(t)
+
+	body := s.getJSON(t, "/health", http.StatusOK)
+	if body["status"] != "healthy" {
+		t.Errorf("got status %v, want healthy", body["status"])
+	}
+}
+
+func TestListHandlers(t *testing.T) {
+	s := newTestServer(t)
+
+	tests := []struct {
+		path      string
+		wantKind  string
+		wantNames []string
+	}{
+		{"/api/v1/providers", "ProviderList", []string{"provider-aws-s3", "provider-helm", "provider-kubernetes"}},
+		{"/api/v1/providerconfigs", "ProviderConfigList", []string{"default", "sandbox"}},
+		{"/api/v1/xrds", "CompositeResourceDefinitionList", []string{"xnetworks.example.org"}},
+		{"/api/v1/compositions", "CompositionList", []string{"xnetworks-aws", "xnetworks-legacy"}},
+		{"/api/v1/functions", "FunctionList", []string{"function-environment-configs", "function-patch-and-transform"}},
+		{"/api/v1/xrs", "CompositeResourceList", []string{"net-1-x7k2p"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			body := s.getJSON(t, tt.path, http.StatusOK)
+
+			if body["kind"] != tt.wantKind {
+				t.Errorf("got kind %v, want %s", body["kind"], tt.wantKind)
+			}
+			if got := itemNames(t, body); strings.Join(got, ",") != strings.Join(tt.wantNames, ",") {
+				t.Errorf("got items %v, want %v", got, tt.wantNames)
+			}
+			if body["count"] != float64(len(tt.wantNames)) {
+				t.Errorf("got count %v, want %d", body["count"], len(tt.wantNames))
+			}
+		})
+	}
+}
+
+func TestListFilters(t *testing.T) {
+	s := newTestServer(t)
+
+	tests := []struct {
+		name      string
+		query     string
+		wantNames []string
+	}{
+		{"ready", "ready=true", []string{"provider-aws-s3"}},
+		{"not ready", "ready=false", []string{"provider-helm", "provider-kubernetes"}},
+		{"name substring", "name=helm", []string{"provider-helm"}},
+		{"name regex", "name=/^provider-(helm|kubernetes)$/", []string{"provider-helm", "provider-kubernetes"}},
+		{"label selector", "labelSelector=tier%3Dcore", []string{"provider-aws-s3"}},
+		{"field selector", "fieldSelector=metadata.name%3Dprovider-helm", []string{"provider-helm"}},
+		{"sort by age", "sort=age", []string{"provider-helm", "provider-kubernetes", "provider-aws-s3"}},
+		{"sort descending", "sort=-name", []string{"provider-kubernetes", "provider-helm", "provider-aws-s3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := s.getJSON(t, "/api/v1/providers?"+tt.query, http.StatusOK)
+			if got := itemNames(t, body); strings.Join(got, ",") != strings.Join(tt.wantNames, ",") {
+				t.Errorf("got items %v, want %v", got, tt.wantNames)
+			}
+		})
+	}
+}
+
+func TestListInvalidQuery(t *testing.T) {
+	s := newTestServer(t)
+
+	for _, query := range []string{"ready=maybe", "sort=size", "limit=-1", "name=/[/", "continue=garbage"} {
+		t.Run(query, func(t *testing.T) {
+			s.getJSON(t, "/api/v1/providers?"+query, http.StatusBadRequest)
+		})
+	}
+
+	// A zero limit returns every item
+	all := itemNames(t, s.getJSON(t, "/api/v1/providers", http.StatusOK))
+	if got := itemNames(t, s.getJSON(t, "/api/v1/providers?limit=0", http.StatusOK)); !slices.Equal(got, all) {
+		t.Errorf("got items %v with limit=0, want %v", got, all)
+	}
+}
+
+// rejectingReader fails the listings as the API server does for a selector it rejects
+type rejectingReader struct {
+	k8s.ResourceReader
+}
+
+func (rejectingReader) ListXRs(ctx context.Context, gvr schema.GroupVersionResource, namespace string, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	return nil, apierrors.NewBadRequest("unable to parse requirement")
+}
+
+func (rejectingReader) ListResources(ctx context.Context, gvr schema.GroupVersionResource, namespace string, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	return nil, apierrors.NewBadRequest("unable to parse requirement")
+}
+
+func (rejectingReader) ListProviderConfigs(ctx context.Context, gvr schema.GroupVersionResource, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	return nil, apierrors.NewBadRequest("unable to parse requirement")
+}
+
+func TestListRejectedSelector(t *testing.T) {
+	s := newWrappedTestServer(t, func(client k8s.ResourceReader) k8s.ResourceReader {
+		return rejectingReader{client}
+	})
+
+	// Listings over several kinds fail instead of returning the kinds that could be listed
+	for _, path := range []string{"/api/v1/xrs", "/api/v1/providerconfigs", "/api/v1/usages", "/api/v1/namespace-resources"} {
+		t.Run(path, func(t *testing.T) {
+			s.getJSON(t, path+"?fieldSelector=spec.unknown=x", http.StatusBadRequest)
+		})
+	}
+}
+
+func TestListPagination(t *testing.T) {
+	s := newTestServer(t)
+
+	for _, query := range []string{"limit=2", "limit=2&sort=-name"} {
+		t.Run(query, func(t *testing.T) {
+			var names []string
+			path := "/api/v1/providers?" + query
+			for pages := 0; ; pages++ {
+				if pages > 3 {
+					t.Fatal("pagination did not terminate")
+				}
+
+				body := s.getJSON(t, path, http.StatusOK)
+				page := itemNames(t, body)
+				if len(page) > 2 {
+					t.Fatalf("got %d items, want at most 2", len(page))
+				}
+				names = append(names, page...)
+
+				next, _ := body["continue"].(string)
+				if next == "" {
+					break
+				}
+				path = "/api/v1/providers?" + query + "&continue=" + next
+			}
+
+			if len(names) != 3 {
+				t.Errorf("got %v across pages, want 3 providers", names)
+			}
+		})
+	}
+}
+
+// TestPaginateCursor checks that a page resumes after the last item of the previous one
+// when items before it are removed between the requests
+func TestPaginateCursor(t *testing.T) {
+	created := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	providers := func() []models.Provider {
+		var items []models.Provider
+		for i, name := range []string{"a", "b", "c", "d", "e"} {
+			provider := models.Provider{BaseResource: models.BaseResource{Kind: "Provider", Metadata: models.Metadata{
+				Name: name,
+				// Newest first by name
+				CreationTimestamp: created.Add(-time.Duration(i) * time.Hour),
+			}}}
+			provider.Status.Ready = i%2 == 0
+			items = append(items, provider)
+		}
+		return items
+	}
+	names := func(items []models.Provider) []string {
+		var names []string
+		for _, item := range items {
+			names = append(names, item.Metadata.Name)
+		}
+		return names
+	}
+
+	for _, tc := range []struct {
+		sort string
+		want []string
+	}{
+		{sort: "name", want: []string{"a", "b", "c", "d", "e"}},
+		{sort: "-name", want: []string{"e", "d", "c", "b", "a"}},
+		{sort: "age", want: []string{"e", "d", "c", "b", "a"}},
+		{sort: "ready", want: []string{"b", "d", "a", "c", "e"}},
+		{sort: "-ready", want: []string{"e", "c", "a", "d", "b"}},
+	} {
+		t.Run(tc.sort, func(t *testing.T) {
+			page := pageRequest{Limit: 2, Sort: strings.TrimPrefix(tc.sort, "-"), Descending: strings.HasPrefix(tc.sort, "-")}
+
+			first, next := paginate(providers(), page)
+			if got := names(first); !slices.Equal(got, tc.want[:2]) {
+				t.Fatalf("got first page %v, want %v", got, tc.want[:2])
+			}
+
+			// The first item of the first page is deleted before the next request
+			var remaining []models.Provider
+			for _, item := range providers() {
+				if item.Metadata.Name != tc.want[0] {
+					remaining = append(remaining, item)
+				}
+			}
+			data, _ := base64.RawURLEncoding.DecodeString(next)
+			if err := json.Unmarshal(data, &page.cursor); err != nil {
+				t.Fatal(err)
+			}
+
+			second, _ := paginate(remaining, page)
+			if got := names(second); !slices.Equal(got, tc.want[2:4]) {
+				t.Errorf("got second page %v, want %v", got, tc.want[2:4])
+			}
+		})
+	}
+}
+
+func TestClusterResources(t *testing.T) {
+	s := newTestServer(t)
+
+	body := s.getJSON(t, "/api/v1/cluster-resources?kind=Provider", http.StatusOK)
+	if body["count"] != float64(3) {
+		t.Errorf("got count %v, want 3 providers", body["count"])
+	}
+
+	body = s.getJSON(t, "/api/v1/cluster-resources", http.StatusOK)
+	kinds := make(map[string]bool)
+	for _, item := range body["items"].([]interface{}) {
+		kinds[item.(map[string]interface{})["kind"].(string)] = true
+	}
+	for _, kind := range []string{"Provider", "CompositeResourceDefinition", "Composition", "Function"} {
+		if !kinds[kind] {
+			t.Errorf("cluster resources do not contain a %s", kind)
+		}
+	}
+}
+
+func TestExportResource(t *testing.T) {
+	s := newTestServer(t)
+
+	t.Run("yaml strips managed fields", func(t *testing.T) {
+		w := s.do(t, http.MethodGet, "/api/v1/resources/buckets.s3.aws.upbound.io/_/logs-bucket/raw?format=yaml")
+		if w.Code != http.StatusOK {
+			t.Fatalf("got status %d: %s", w.Code, w.Body.String())
+		}
+		if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/yaml") {
+			t.Errorf("got content type %q, want application/yaml", ct)
+		}
+		if strings.Contains(w.Body.String(), "managedFields") {
+			t.Error("managedFields were not stripped")
+		}
+		if !strings.Contains(w.Body.String(), "region: eu-west-1") {
+			t.Errorf("spec missing from export:\n%s", w.Body.String())
+		}
+	})
+
+	t.Run("json strips status", func(t *testing.T) {
+		body := s.getJSON(t, "/api/v1/resources/Bucket/_/logs-bucket/raw?strip=status", http.StatusOK)
+		if _, ok := body["status"]; ok {
+			t.Error("status was not stripped")
+		}
+	})
+
+	t.Run("metadata strips owners and kubectl annotations", func(t *testing.T) {
+		body := s.getJSON(t, "/api/v1/resources/Bucket/_/net-0-artifacts/raw?strip=metadata", http.StatusOK)
+		metadata := body["metadata"].(map[string]interface{})
+		for _, field := range []string{"ownerReferences", "uid", "resourceVersion"} {
+			if _, ok := metadata[field]; ok {
+				t.Errorf("%s was not stripped", field)
+			}
+		}
+		annotations, _ := metadata["annotations"].(map[string]interface{})
+		if _, ok := annotations[lastAppliedAnnotation]; ok || annotations["crossplane.io/composition-resource-name"] != "artifacts" {
+			t.Errorf("got annotations %v, want only the last applied configuration stripped", annotations)
+		}
+	})
+
+	t.Run("namespaced claim", func(t *testing.T) {
+		body := s.getJSON(t, "/api/v1/resources/networks/team-a/net-1/raw", http.StatusOK)
+		if body["kind"] != "Network" {
+			t.Errorf("got kind %v, want Network", body["kind"])
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		s.getJSON(t, "/api/v1/resources/widgets/_/foo/raw", http.StatusNotFound)
+		s.getJSON(t, "/api/v1/resources/Bucket/_/missing/raw", http.StatusNotFound)
+		s.getJSON(t, "/api/v1/resources/Bucket/_/logs-bucket/raw?strip=spec", http.StatusBadRequest)
+		s.getJSON(t, "/api/v1/resources/Bucket/_/logs-bucket/raw?format=xml", http.StatusBadRequest)
+	})
+}
+
+func TestResourceHistory(t *testing.T) {
+	s := newTestServer(t)
+
+	key := history.ObjectKey{Group: "s3.aws.upbound.io", Kind: "Bucket", Name: "logs-bucket"}
+	for _, transition := range []models.ConditionTransition{
+		{Type: "Synced", Status: "True"},
+		{Type: "Ready", Status: "False", Reason: "Creating"},
+		{Type: "Ready", Status: "True", PreviousStatus: "False"},
+	} {
+		if err := s.history.Append(key, transition); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	body := s.getJSON(t, "/api/v1/resources/buckets/_/logs-bucket/history", http.StatusOK)
+	if body["count"] != float64(3) {
+		t.Errorf("got count %v, want 3", body["count"])
+	}
+
+	body = s.getJSON(t, "/api/v1/resources/buckets/_/logs-bucket/history?type=Ready", http.StatusOK)
+	if body["count"] != float64(2) {
+		t.Errorf("got count %v, want 2 Ready transitions", body["count"])
+	}
+
+	// Events are recorded for observed objects, once per increase of their count
+	recorder := history.NewRecorder(s.history)
+	bucket := &unstructured.Unstructured{}
+	bucket.SetAPIVersion("s3.aws.upbound.io/v1beta1")
+	bucket.SetKind("Bucket")
+	bucket.SetName("logs-bucket")
+	recorder.OnAdd(bucket, true)
+
+	event := func(name, kind string, count int32) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID(name)},
+			InvolvedObject: corev1.ObjectReference{APIVersion: "s3.aws.upbound.io/v1beta1", Kind: kind, Name: "logs-bucket"},
+			Type:           corev1.EventTypeWarning,
+			Reason:         "CannotObserveExternalResource",
+			Count:          count,
+		}
+	}
+	recorder.OnAdd(event("throttled", "Bucket", 1), false)
+	recorder.OnUpdate(event("throttled", "Bucket", 1), event("throttled", "Bucket", 1))
+	recorder.OnUpdate(event("throttled", "Bucket", 1), event("throttled", "Bucket", 3))
+	recorder.OnAdd(event("unobserved", "Object", 1), false)
+
+	body = s.getJSON(t, "/api/v1/resources/buckets/_/logs-bucket/history", http.StatusOK)
+	events, _ := body["events"].([]interface{})
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2: %v", len(events), events)
+	}
+	if last := events[1].(map[string]interface{}); last["count"] != float64(3) || last["reason"] != "CannotObserveExternalResource" {
+		t.Errorf("got last event %v, want count 3", last)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	s := newTestServer(t)
+
+	body := s.getJSON(t, "/api/v1/search?q=acme-logs", http.StatusOK)
+	items, _ := body["items"].([]interface{})
+	if len(items) != 1 {
+		t.Fatalf("got %d hits, want 1: %v", len(items), body)
+	}
+	hit := items[0].(map[string]interface{})
+	if hit["name"] != "logs-bucket" || hit["category"] != k8s.CategoryManaged {
+		t.Errorf("got hit %v, want managed resource logs-bucket", hit)
+	}
+
+	s.getJSON(t, "/api/v1/search", http.StatusBadRequest)
+}
+
+func TestSnapshots(t *testing.T) {
+	s := newTestServer(t)
+
+	w := s.do(t, http.MethodPost, "/api/v1/snapshots")
+	if w.Code != http.StatusCreated {
+		t.Fatalf("got status %d: %s", w.Code, w.Body.String())
+	}
+	var created models.Snapshot
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+	if created.Count == 0 {
+		t.Error("snapshot captured no objects")
+	}
+
+	// Snapshots captured in a row are all kept, even within the same millisecond
+	if w := s.do(t, http.MethodPost, "/api/v1/snapshots"); w.Code != http.StatusCreated {
+		t.Fatalf("got status %d: %s", w.Code, w.Body.String())
+	}
+	body := s.getJSON(t, "/api/v1/snapshots", http.StatusOK)
+	if body["count"] != float64(2) {
+		t.Errorf("got %v snapshots, want 2", body["count"])
+	}
+
+	body = s.getJSON(t, "/api/v1/snapshots/diff?from="+created.ID, http.StatusOK)
+	for _, field := range []string{"added", "removed", "changed"} {
+		if changes, _ := body[field].([]interface{}); len(changes) != 0 {
+			t.Errorf("got %s %v, want no difference with the live state", field, changes)
+		}
+	}
+
+	s.getJSON(t, "/api/v1/snapshots/unknown", http.StatusNotFound)
+
+	if w := s.do(t, http.MethodDelete, "/api/v1/snapshots/"+created.ID); w.Code != http.StatusNoContent {
+		t.Errorf("got status %d on delete, want 204", w.Code)
+	}
+	s.getJSON(t, "/api/v1/snapshots/"+created.ID, http.StatusNotFound)
+}
+
+func TestSyncFailuresReport(t *testing.T) {
+	s := newTestServer(t)
+
+	w := s.do(t, http.MethodGet, "/api/v1/reports/sync-failures")
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", w.Code, w.Body.String())
+	}
+	var r models.SyncFailureReport
+	if err := json.Unmarshal(w.Body.Bytes(), &r); err != nil {
+		t.Fatal(err)
+	}
+
+	if r.ManagedCount != 4 || r.UnsyncedCount != 2 {
+		t.Errorf("got %d managed and %d unsynced, want 4 and 2", r.ManagedCount, r.UnsyncedCount)
+	}
+	if len(r.Groups) != 1 {
+		t.Fatalf("got %d groups, want the 2 buckets grouped by their normalized error: %+v", len(r.Groups), r.Groups)
+	}
+	g := r.Groups[0]
+	if g.Provider != "provider-aws-s3" || g.Kind != "Bucket" || g.Count != 2 || g.ProviderConfigs["default"] != 2 {
+		t.Errorf("got group %+v", g)
+	}
+	if want := time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC); !g.OldestFailure.Equal(want) {
+		t.Errorf("got oldest failure %v, want %v", g.OldestFailure, want)
+	}
+
+	body := s.getJSON(t, "/api/v1/reports/sync-failures?provider=provider-helm", http.StatusOK)
+	if body["unsyncedCount"] != float64(0) {
+		t.Errorf("got %v unsynced for provider-helm, want 0", body["unsyncedCount"])
+	}
+	s.getJSON(t, "/api/v1/reports/sync-failures?minUpdates=0", http.StatusBadRequest)
+}
+
+func TestStuckDeletionsReport(t *testing.T) {
+	s := newTestServer(t)
+
+	w := s.do(t, http.MethodGet, "/api/v1/reports/stuck-deletions")
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", w.Code, w.Body.String())
+	}
+	var r models.StuckDeletionReport
+	if err := json.Unmarshal(w.Body.Bytes(), &r); err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Count != 1 {
+		t.Fatalf("got %d terminating objects, want 1: %+v", r.Count, r.Resources)
+	}
+	res := r.Resources[0]
+	if res.Name != "net-1-x7k2p" || res.Category != k8s.CategoryComposite || res.TerminatingSeconds <= 0 {
+		t.Errorf("got %+v", res)
+	}
+	if len(res.Finalizers) != 1 || res.Finalizers[0] != "composite.apiextensions.crossplane.io" {
+		t.Errorf("got finalizers %v", res.Finalizers)
+	}
+	if len(res.BlockingUsages) != 1 {
+		t.Fatalf("got blocking usages %+v, want the Usage of its composed bucket", res.BlockingUsages)
+	}
+	if u := res.BlockingUsages[0]; u.Name != "protect-logs-bucket" || u.Of.Name != "logs-bucket" || u.By == nil || u.By.Name != "backups-bucket" {
+		t.Errorf("got blocking usage %+v", u)
+	}
+
+	body := s.getJSON(t, "/api/v1/reports/stuck-deletions?category=managed", http.StatusOK)
+	if body["count"] != float64(0) {
+		t.Errorf("got %v terminating managed resources, want 0", body["count"])
+	}
+	body = s.getJSON(t, "/api/v1/reports/stuck-deletions?minAge=876000h", http.StatusOK)
+	if body["count"] != float64(0) {
+		t.Errorf("got %v objects terminating for 100 years, want 0", body["count"])
+	}
+	s.getJSON(t, "/api/v1/reports/stuck-deletions?minAge=soon", http.StatusBadRequest)
+}
+
+func TestUsages(t *testing.T) {
+	s := newTestServer(t)
+
+	w := s.do(t, http.MethodGet, "/api/v1/usages?sort=name")
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", w.Code, w.Body.String())
+	}
+	var list struct {
+		Kind  string         `json:"kind"`
+		Items []models.Usage `json:"items"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatal(err)
+	}
+	if list.Kind != "UsageList" || len(list.Items) != 2 {
+		t.Fatalf("got %s with %d usages, want UsageList with 2", list.Kind, len(list.Items))
+	}
+
+	keep, protect := list.Items[0], list.Items[1]
+	if keep.Spec.Of.Namespace != "team-a" || !keep.Spec.Of.Found || !keep.Spec.Of.Ready || keep.Spec.By != nil {
+		t.Errorf("got namespaced usage %+v, want its claim resolved in its namespace", keep.Spec)
+	}
+	if !protect.Spec.Of.Found || protect.Spec.Of.Ready || protect.Spec.By == nil || !protect.Spec.By.Found {
+		t.Errorf("got usage %+v, want both buckets found and logs-bucket not ready", protect.Spec)
+	}
+
+	t.Run("detail of a protected resource", func(t *testing.T) {
+		var detail models.ResourceDetail
+		if err := json.Unmarshal(s.do(t, http.MethodGet, "/api/v1/resources/buckets/_/logs-bucket").Body.Bytes(), &detail); err != nil {
+			t.Fatal(err)
+		}
+		if detail.Metadata.Name != "logs-bucket" || len(detail.Protection.ProtectedBy) != 1 || len(detail.Protection.Protects) != 0 {
+			t.Errorf("got %+v", detail)
+		}
+	})
+
+	t.Run("detail of a using resource", func(t *testing.T) {
+		var detail models.ResourceDetail
+		if err := json.Unmarshal(s.do(t, http.MethodGet, "/api/v1/resources/buckets/_/backups-bucket").Body.Bytes(), &detail); err != nil {
+			t.Fatal(err)
+		}
+		if len(detail.Protection.Protects) != 1 || detail.Protection.Protects[0].Spec.Of.Name != "logs-bucket" {
+			t.Errorf("got protection %+v", detail.Protection)
+		}
+	})
+
+	t.Run("detail of a deleting resource", func(t *testing.T) {
+		body := s.getJSON(t, "/api/v1/resources/xnetworks/_/net-1-x7k2p", http.StatusOK)
+		if body["deletionTimestamp"] == nil || body["finalizers"] == nil {
+			t.Errorf("got %v, want the deletion timestamp and finalizers", body)
+		}
+	})
+
+	s.getJSON(t, "/api/v1/resources/buckets/_/missing", http.StatusNotFound)
+}
+
+func TestOrphansReport(t *testing.T) {
+	s := newTestServer(t)
+
+	w := s.do(t, http.MethodGet, "/api/v1/reports/orphans")
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", w.Code, w.Body.String())
+	}
+	var r models.OrphanReport
+	if err := json.Unmarshal(w.Body.Bytes(), &r); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(r.Orphaned) != 1 || r.Orphaned[0].Name != "net-0-artifacts" || r.Orphaned[0].Owner == nil || r.Orphaned[0].Composite != "net-0-q8w3e" {
+		t.Errorf("got orphaned %+v, want net-0-artifacts owned by the missing net-0-q8w3e", r.Orphaned)
+	}
+
+	var unowned []string
+	for _, o := range r.Unowned {
+		unowned = append(unowned, o.Name)
+	}
+	slices.Sort(unowned)
+	if want := []string{"assets-bucket", "backups-bucket"}; !slices.Equal(unowned, want) {
+		t.Errorf("got unowned %v, want %v", unowned, want)
+	}
+
+	if len(r.MissingRefs) != 1 || len(r.MissingRefs[0].Missing) != 1 || r.MissingRefs[0].Missing[0].Name != "net-1-flow-logs" {
+		t.Errorf("got missing resource refs %+v, want net-1-flow-logs of net-1-x7k2p", r.MissingRefs)
+	}
+}
+
+func TestPausedReport(t *testing.T) {
+	s := newTestServer(t)
+
+	w := s.do(t, http.MethodGet, "/api/v1/reports/paused")
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", w.Code, w.Body.String())
+	}
+	var r models.PausedReport
+	if err := json.Unmarshal(w.Body.Bytes(), &r); err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Count != 1 {
+		t.Fatalf("got %d paused objects, want 1: %+v", r.Count, r.Resources)
+	}
+	p := r.Resources[0]
+	if p.Name != "net-1" || p.Category != k8s.CategoryClaim || !p.Annotated || p.PausedBy != "kubectl-annotate" || p.Operation != "Update" {
+		t.Errorf("got %+v", p)
+	}
+	if want := time.Date(2025, 3, 20, 10, 0, 0, 0, time.UTC); p.PausedAt == nil || !p.PausedAt.Equal(want) {
+		t.Errorf("got paused at %v, want %v", p.PausedAt, want)
+	}
+	if p.PausedSince == nil {
+		t.Error("got no ReconcilePaused transition time")
+	}
+
+	body := s.getJSON(t, "/api/v1/reports/paused?category=managed", http.StatusOK)
+	if body["count"] != float64(0) {
+		t.Errorf("got %v paused managed resources, want 0", body["count"])
+	}
+	s.getJSON(t, "/api/v1/reports/paused?minAge=forever", http.StatusBadRequest)
+
+	// The resource endpoint reports the pause too
+	body = s.getJSON(t, "/api/v1/resources/networks/team-a/net-1", http.StatusOK)
+	if body["paused"] != true || body["pausedReason"] != models.ReasonReconcilePaused {
+		t.Errorf("got paused %v with reason %v, want true with %s", body["paused"], body["pausedReason"], models.ReasonReconcilePaused)
+	}
+	body = s.getJSON(t, "/api/v1/resources/Bucket/_/logs-bucket", http.StatusOK)
+	if _, ok := body["paused"]; ok {
+		t.Errorf("got paused %v for a reconciled bucket", body["paused"])
+	}
+}
+
+func TestPolicyAuditReport(t *testing.T) {
+	s := newTestServer(t)
+
+	w := s.do(t, http.MethodGet, "/api/v1/reports/policies")
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", w.Code, w.Body.String())
+	}
+	var r models.PolicyAuditReport
+	if err := json.Unmarshal(w.Body.Bytes(), &r); err != nil {
+		t.Fatal(err)
+	}
+
+	if r.ManagedCount != 4 || len(r.Combinations) != 3 {
+		t.Fatalf("got %d resources in combinations %+v, want 4 in 3", r.ManagedCount, r.Combinations)
+	}
+	for _, c := range r.Combinations {
+		if c.Provider != "provider-aws-s3" {
+			t.Errorf("got provider %q, want provider-aws-s3", c.Provider)
+		}
+		defaults := c.DeletionPolicy == "Delete" && slices.Equal(c.ManagementPolicies, []string{"*"})
+		if defaults && (c.Count != 2 || !c.DeletesExternal) {
+			t.Errorf("got default combination %+v, want 2 resources deleting their external resource", c)
+		}
+	}
+
+	var findings []string
+	for _, f := range r.Findings {
+		findings = append(findings, f.Rule+" "+f.Name)
+	}
+	if want := []string{"OrphanOverridden backups-bucket", "ProtectedDelete logs-bucket"}; !slices.Equal(findings, want) {
+		t.Errorf("got findings %v, want %v", findings, want)
+	}
+
+	body := s.getJSON(t, "/api/v1/reports/policies?protectedSelector=", http.StatusOK)
+	if findings, _ := body["findings"].([]interface{}); len(findings) != 1 {
+		t.Errorf("got findings %v without protected selector, want only OrphanOverridden", findings)
+	}
+	s.getJSON(t, "/api/v1/reports/policies?protectedSelector=a%20b", http.StatusBadRequest)
+
+	// The resource endpoint reports the policies set on a managed resource
+	w = s.do(t, http.MethodGet, "/api/v1/resources/Bucket/_/assets-bucket")
+	var detail models.ResourceDetail
+	if err := json.Unmarshal(w.Body.Bytes(), &detail); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(detail.ManagementPolicies, []string{"Observe"}) || detail.DeletionPolicy != "Orphan" {
+		t.Errorf("got policies %v and %q, want [Observe] and Orphan", detail.ManagementPolicies, detail.DeletionPolicy)
+	}
+}
+
+func TestProviderConfigUsages(t *testing.T) {
+	s := newTestServer(t)
+
+	w := s.do(t, http.MethodGet, "/api/v1/providerconfigs?sort=name")
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", w.Code, w.Body.String())
+	}
+	var list struct {
+		Items []models.ProviderConfig `json:"items"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Items) != 2 {
+		t.Fatalf("got %d provider configs, want 2", len(list.Items))
+	}
+
+	def, sandbox := list.Items[0], list.Items[1]
+	if def.Spec.CredentialsSource != "Secret" || def.Spec.CredentialsSecretRef == nil || def.Spec.CredentialsSecretRef.Name != "aws-credentials" || def.Status.Users != 2 {
+		t.Errorf("got default config %+v", def)
+	}
+	var used []string
+	for _, u := range def.Usages {
+		used = append(used, u.Resource.Kind+"/"+u.Resource.Name)
+	}
+	slices.Sort(used)
+	if want := []string{"Bucket/assets-bucket", "Bucket/logs-bucket"}; !slices.Equal(used, want) {
+		t.Errorf("got usages %v, want %v", used, want)
+	}
+
+	if sandbox.Spec.CredentialsSource != "IRSA" || sandbox.Status.Users != 0 || len(sandbox.Usages) != 0 {
+		t.Errorf("got unused sandbox config %+v", sandbox)
+	}
+}
+
+func TestProviderRuntime(t *testing.T) {
+	s := newTestServer(t)
+
+	w := s.do(t, http.MethodGet, "/api/v1/providers/provider-aws-s3")
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", w.Code, w.Body.String())
+	}
+	var provider models.ProviderDetail
+	if err := json.Unmarshal(w.Body.Bytes(), &provider); err != nil {
+		t.Fatal(err)
+	}
+
+	runtime := provider.Runtime
+	if runtime.RuntimeConfig.Name != "default" || !runtime.RuntimeConfig.Found {
+		t.Errorf("got runtime config %+v", runtime.RuntimeConfig)
+	}
+	if d := runtime.Deployment; d == nil || d.Name != "provider-aws-s3-6a3d4c9f1b2e" || d.Replicas != 1 || d.ReadyReplicas != 1 ||
+		d.Image != "xpkg.upbound.io/upbound/provider-aws-s3:v1.21.0" {
+		t.Fatalf("got deployment %+v", d)
+	}
+	if len(runtime.Pods) != 1 {
+		t.Fatalf("got %d pods, want 1", len(runtime.Pods))
+	}
+	if pod := runtime.Pods[0]; !pod.Ready || pod.Restarts != 3 || pod.LastTermination != "OOMKilled" || pod.LastTerminationTime == nil || pod.Node != "worker-1" {
+		t.Errorf("got pod %+v", pod)
+	}
+
+	// Packages without a running Deployment still resolve their runtime config
+	w = s.do(t, http.MethodGet, "/api/v1/functions/function-patch-and-transform")
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", w.Code, w.Body.String())
+	}
+	var function models.FunctionDetail
+	if err := json.Unmarshal(w.Body.Bytes(), &function); err != nil {
+		t.Fatal(err)
+	}
+	if function.Runtime.Deployment != nil || len(function.Runtime.Pods) != 0 || !function.Runtime.RuntimeConfig.Found {
+		t.Errorf("got function runtime %+v", function.Runtime)
+	}
+
+	if w := s.do(t, http.MethodGet, "/api/v1/providers/provider-missing"); w.Code != http.StatusNotFound {
+		t.Errorf("got status %d for a missing provider, want 404", w.Code)
+	}
+}
+
+func TestPackageLogs(t *testing.T) {
+	s := newTestServer(t)
+
+	w := s.do(t, http.MethodGet, "/api/v1/providers/provider-aws-s3/logs?tailLines=10")
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/event-stream") {
+		t.Errorf("got content type %q, want text/event-stream", ct)
+	}
+	body := w.Body.String()
+	// The fake clientset serves "fake logs" for every pod
+	for _, want := range []string{"event:log", `"pod":"provider-aws-s3-6a3d4c9f1b2e-7d9c8-x2k4q"`, `"container":"package-runtime"`, `"line":"fake logs"`, "event:end"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("logs stream does not contain %s:\n%s", want, body)
+		}
+	}
+
+	for path, want := range map[string]int{
+		"/api/v1/providers/provider-aws-s3/logs?tailLines=-1":   http.StatusBadRequest,
+		"/api/v1/providers/provider-aws-s3/logs?sinceSeconds=0": http.StatusBadRequest,
+		"/api/v1/providers/provider-aws-s3/logs?follow=maybe":   http.StatusBadRequest,
+		"/api/v1/providers/provider-missing/logs":               http.StatusNotFound,
+		"/api/v1/functions/function-patch-and-transform/logs":   http.StatusNotFound,
+	} {
+		if w := s.do(t, http.MethodGet, path); w.Code != want {
+			t.Errorf("GET %s: got status %d, want %d: %s", path, w.Code, want, w.Body.String())
+		}
+	}
+}
+
+// slowLogsReader serves pod logs as a followed stream writing a line every interval
+type slowLogsReader struct {
+	k8s.ResourceReader
+	lines    int
+	interval time.Duration
+}
+
+func (r slowLogsReader) StreamPodLogs(ctx context.Context, namespace, name string, opts *corev1.PodLogOptions) (io.ReadCloser, error) {
+	reader, writer := io.Pipe()
+	go func() {
+		defer writer.Close()
+		for i := 0; i < r.lines; i++ {
+			time.Sleep(r.interval)
+			if _, err := fmt.Fprintf(writer, "line %d\n", i); err != nil {
+				return
+			}
+		}
+	}()
+	return reader, nil
+}
+
+func TestPackageLogsFollowPastWriteTimeout(t *testing.T) {
+	s := newWrappedTestServer(t, func(client k8s.ResourceReader) k8s.ResourceReader {
+		return slowLogsReader{ResourceReader: client, lines: 6, interval: 100 * time.Millisecond}
+	})
+
+	server := httptest.NewUnstartedServer(s.router)
+	server.Config.WriteTimeout = 200 * time.Millisecond
+	server.Start()
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/v1/providers/provider-aws-s3/logs?follow=true")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("stream cut after %d bytes: %v", len(body), err)
+	}
+	for _, want := range []string{`"line":"line 5"`, "event:end"} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("logs stream does not contain %s:\n%s", want, body)
+		}
+	}
+}
+
+// partialLogsReader lists every pod twice and serves the logs of the first pod only,
+// as when the pod log subresource becomes unavailable while opening the streams
+type partialLogsReader struct {
+	k8s.ResourceReader
+	opened []*closeRecorder
+}
+
+// closeRecorder is a log stream that records whether it was closed
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (r *closeRecorder) Close() error {
+	r.closed = true
+	return nil
+}
+
+func (r *partialLogsReader) ListPods(ctx context.Context, namespace string, opts metav1.ListOptions) (*corev1.PodList, error) {
+	pods, err := r.ResourceReader.ListPods(ctx, namespace, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, pod := range slices.Clone(pods.Items) {
+		pod.Name += "-copy"
+		pods.Items = append(pods.Items, pod)
+	}
+	return pods, nil
+}
+
+func (r *partialLogsReader) StreamPodLogs(ctx context.Context, namespace, name string, opts *corev1.PodLogOptions) (io.ReadCloser, error) {
+	if len(r.opened) > 0 {
+		return nil, k8s.ErrLogsUnavailable
+	}
+	stream := &closeRecorder{Reader: strings.NewReader("line\n")}
+	r.opened = append(r.opened, stream)
+	return stream, nil
+}
+
+func TestPackageLogsUnavailableClosesStreams(t *testing.T) {
+	reader := &partialLogsReader{}
+	s := newWrappedTestServer(t, func(client k8s.ResourceReader) k8s.ResourceReader {
+		reader.ResourceReader = client
+		return reader
+	})
+
+	s.getJSON(t, "/api/v1/providers/provider-aws-s3/logs", http.StatusNotImplemented)
+	if len(reader.opened) != 1 || !reader.opened[0].closed {
+		t.Errorf("streams opened before the failure were not closed")
+	}
+}
+
+func TestEnvironmentConfigs(t *testing.T) {
+	s := newTestServer(t)
+
+	body := s.getJSON(t, "/api/v1/environmentconfigs", http.StatusOK)
+	if got := itemNames(t, body); !slices.Equal(got, []string{"network-defaults", "shared-tags", "us-east-1"}) {
+		t.Errorf("got environment configs %v", got)
+	}
+
+	w := s.do(t, http.MethodGet, "/api/v1/environmentconfigs/network-defaults")
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", w.Code, w.Body.String())
+	}
+	var config models.EnvironmentConfig
+	if err := json.Unmarshal(w.Body.Bytes(), &config); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(config.DataKeys, []string{"cidrBlock", "dns"}) || config.Data["cidrBlock"] != "10.0.0.0/16" {
+		t.Errorf("got environment config %+v", config)
+	}
+	if w := s.do(t, http.MethodGet, "/api/v1/environmentconfigs/missing"); w.Code != http.StatusNotFound {
+		t.Errorf("got status %d for a missing environment config, want 404", w.Code)
+	}
+
+	w = s.do(t, http.MethodGet, "/api/v1/compositions/xnetworks-aws")
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", w.Code, w.Body.String())
+	}
+	var composition models.CompositionDetail
+	if err := json.Unmarshal(w.Body.Bytes(), &composition); err != nil {
+		t.Fatal(err)
+	}
+	if len(composition.Environment) != 4 {
+		t.Fatalf("got %d environment selectors, want 4", len(composition.Environment))
+	}
+	for i, want := range []struct {
+		matches []string
+		dynamic bool
+		problem bool
+	}{
+		{matches: []string{"network-defaults"}},
+		{matches: []string{"network-defaults", "shared-tags"}},
+		{matches: []string{"us-east-1"}, dynamic: true},
+		{matches: []string{}, problem: true},
+	} {
+		got := composition.Environment[i]
+		if got.Step != "environment" || !slices.Equal(got.Matches, want.matches) || got.Dynamic != want.dynamic || (got.Problem != "") != want.problem {
+			t.Errorf("selector %d: got %+v", i, got)
+		}
+	}
+}
+
+func TestResolveEnvironmentSelectors(t *testing.T) {
+	config := func(name string, labels map[string]interface{}, data map[string]interface{}) unstructured.Unstructured {
+		return unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apiextensions.crossplane.io/v1beta1", "kind": "EnvironmentConfig",
+			"metadata": map[string]interface{}{"name": name, "labels": labels},
+			"data":     data,
+		}}
+	}
+	configs := []unstructured.Unstructured{
+		config("eu", map[string]interface{}{"tier": "shared"}, map[string]interface{}{"priority": int64(2)}),
+		config("us", map[string]interface{}{"tier": "shared"}, map[string]interface{}{"priority": int64(1)}),
+		config("unranked", map[string]interface{}{"tier": "edge"}, map[string]interface{}{}),
+	}
+	labels := func(tier string) []models.EnvironmentLabel {
+		return []models.EnvironmentLabel{{Key: "tier", Type: models.EnvironmentLabelValue, Value: tier}}
+	}
+	one := 1
+
+	for _, tc := range []struct {
+		name     string
+		selector models.EnvironmentSelector
+		matches  []string
+		problem  bool
+	}{
+		{
+			name:     "single mode with several matches",
+			selector: models.EnvironmentSelector{Mode: models.EnvironmentSelectorSingle, MatchLabels: labels("shared"), SortByFieldPath: "data.priority"},
+			matches:  []string{"us", "eu"}, problem: true,
+		},
+		{
+			name:     "single mode without match",
+			selector: models.EnvironmentSelector{Mode: models.EnvironmentSelectorSingle, MatchLabels: labels("core"), SortByFieldPath: "metadata.name"},
+			problem:  true,
+		},
+		{
+			name:     "multiple mode without match",
+			selector: models.EnvironmentSelector{Mode: models.EnvironmentSelectorMultiple, MatchLabels: labels("core"), SortByFieldPath: "metadata.name"},
+		},
+		{
+			name:     "multiple mode below minMatch",
+			selector: models.EnvironmentSelector{Mode: models.EnvironmentSelectorMultiple, MatchLabels: labels("core"), SortByFieldPath: "metadata.name", MinMatch: &one},
+			problem:  true,
+		},
+		{
+			name:     "missing sort value",
+			selector: models.EnvironmentSelector{Mode: models.EnvironmentSelectorMultiple, MatchLabels: labels("edge"), SortByFieldPath: "data.priority"},
+			matches:  []string{"unranked"}, problem: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.selector.Type = models.EnvironmentSourceSelector
+			selectors := []models.EnvironmentSelector{tc.selector}
+			resolveEnvironmentSelectors(selectors, configs)
+			got := selectors[0]
+			if !slices.Equal(got.Matches, tc.matches) || (got.Problem != "") != tc.problem {
+				t.Errorf("got matches %v and problem %q", got.Matches, got.Problem)
+			}
+		})
+	}
+}
+
+func TestCompositionRevisions(t *testing.T) {
+	s := newTestServer(t)
+
+	w := s.do(t, http.MethodGet, "/api/v1/compositions/xnetworks-aws/revisions")
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", w.Code, w.Body.String())
+	}
+	var history struct {
+		Items []models.CompositionRevision `json:"items"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &history); err != nil {
+		t.Fatal(err)
+	}
+	if len(history.Items) != 2 {
+		t.Fatalf("got %d revisions, want 2", len(history.Items))
+	}
+
+	latest, first := history.Items[0], history.Items[1]
+	if latest.Revision != 2 || !latest.Latest || first.Latest || first.Diff != nil {
+		t.Errorf("got revisions %+v and %+v", latest, first)
+	}
+	if diff := latest.Diff; diff == nil || diff.From != 1 || !slices.Equal(diff.Pipeline.Added, []string{"environment"}) ||
+		len(diff.Pipeline.Removed) != 0 || diff.Pipeline.Reordered {
+		t.Fatalf("got diff %+v", latest.Diff)
+	}
+	// The input of the patch-and-transform step was removed
+	if changed := latest.Diff.Pipeline.Changed; len(changed) != 1 || changed[0].Name != "patch-and-transform" || changed[0].Changes[0].Path != "input" {
+		t.Errorf("got changed steps %+v", changed)
+	}
+
+	if w := s.do(t, http.MethodGet, "/api/v1/compositions/missing/revisions"); w.Code != http.StatusNotFound {
+		t.Errorf("got status %d for a missing composition, want 404", w.Code)
+	}
+
+	w = s.do(t, http.MethodGet, "/api/v1/xrs?name=net-1-x7k2p")
+	var xrs struct {
+		Items []models.CompositeResource `json:"items"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &xrs); err != nil {
+		t.Fatal(err)
+	}
+	if len(xrs.Items) != 1 {
+		t.Fatalf("got %d XRs, want 1", len(xrs.Items))
+	}
+	xr := xrs.Items[0]
+	if xr.Spec.CompositionUpdatePolicy != models.CompositionUpdateManual || xr.Spec.CompositionRevisionRef == nil ||
+		xr.Status.LatestRevision != "xnetworks-aws-9e8f7a6" || !xr.Status.RevisionLagging {
+		t.Errorf("got XR spec %+v, status %+v", xr.Spec, xr.Status)
+	}
+}
+
+func TestCompositionLint(t *testing.T) {
+	s := newTestServer(t)
+
+	lint := func(name string) models.CompositionLintReport {
+		t.Helper()
+		w := s.do(t, http.MethodGet, "/api/v1/compositions/"+name+"/lint")
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET %s lint: got status %d: %s", name, w.Code, w.Body.String())
+		}
+		var r models.CompositionLintReport
+		if err := json.Unmarshal(w.Body.Bytes(), &r); err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+
+	if r := lint("xnetworks-aws"); !r.CrossplaneV2 || r.Mode != "Pipeline" || len(r.Findings) != 0 {
+		t.Errorf("got lint %+v, want no findings", r)
+	}
+
+	// The fake cluster serves the v2 XRD API, and the XRD schema has no spec.parameters.cidr
+	r := lint("xnetworks-legacy")
+	var rules []string
+	for _, f := range r.Findings {
+		rules = append(rules, f.Rule+" "+f.Path)
+	}
+	want := []string{
+		report.RuleLegacyResourcesMode + " spec.mode",
+		report.RuleUnknownPatchField + " spec.resources[0].patches[1].fromFieldPath",
+	}
+	if !slices.Equal(rules, want) || r.Errors != 1 || r.Warnings != 1 {
+		t.Errorf("got findings %v (%d errors, %d warnings), want %v", rules, r.Errors, r.Warnings, want)
+	}
+
+	if w := s.do(t, http.MethodGet, "/api/v1/compositions/missing/lint"); w.Code != http.StatusNotFound {
+		t.Errorf("got status %d for a missing composition, want 404", w.Code)
+	}
+}
+
+func TestUpgradeReport(t *testing.T) {
+	s := newTestServer(t)
+
+	w := s.do(t, http.MethodGet, "/api/v1/reports/v2-upgrade")
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", w.Code, w.Body.String())
+	}
+	var r models.UpgradeReport
+	if err := json.Unmarshal(w.Body.Bytes(), &r); err != nil {
+		t.Fatal(err)
+	}
+
+	// Blocking objects first, then by kind
+	var got []string
+	for _, o := range r.Objects {
+		var rules []string
+		for _, f := range o.Findings {
+			rules = append(rules, f.Rule)
+		}
+		got = append(got, fmt.Sprintf("%s/%s %v %v", o.Kind, o.Name, o.Blocking, rules))
+	}
+	want := []string{
+		"Composition/xnetworks-legacy true [NativePatchAndTransform]",
+		"ControllerConfig/debug true [ControllerConfig]",
+		"CompositeResourceDefinition/xnetworks.example.org false [LegacyXRD ClaimNames]",
+		"Network/net-1 false [Claim]",
+		"Usage/protect-logs-bucket false [LegacyUsage]",
+		"XNetwork/net-1-x7k2p false [LegacyComposite]",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got objects\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if r.Ready || r.Removed != 2 || r.Deprecated != 5 || r.Rules[report.RuleControllerConfig] != 1 {
+		t.Errorf("got ready %v, %d removed, %d deprecated, rules %v", r.Ready, r.Removed, r.Deprecated, r.Rules)
+	}
+}
+
+func TestCompositionSelection(t *testing.T) {
+	s := newTestServer(t)
+
+	for _, tc := range []struct {
+		path       string
+		method     string
+		candidates []string
+	}{
+		{path: "/api/v1/resources/xnetworks/_/net-1-x7k2p", method: models.SelectionReference},
+		{path: "/api/v1/resources/networks/team-a/net-1", method: models.SelectionSelector, candidates: []string{"xnetworks-aws"}},
+	} {
+		w := s.do(t, http.MethodGet, tc.path)
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET %s: got status %d: %s", tc.path, w.Code, w.Body.String())
+		}
+		var detail models.ResourceDetail
+		if err := json.Unmarshal(w.Body.Bytes(), &detail); err != nil {
+			t.Fatal(err)
+		}
+		selection := detail.CompositionSelection
+		if selection == nil {
+			t.Fatalf("GET %s: no composition selection", tc.path)
+		}
+		if selection.Method != tc.method || !slices.Equal(selection.Candidates, tc.candidates) || len(selection.Warnings) != 0 ||
+			selection.CompositeType.Kind != "XNetwork" {
+			t.Errorf("GET %s: got selection %+v", tc.path, selection)
+		}
+	}
+
+	// Other objects have no composition
+	body := s.getJSON(t, "/api/v1/resources/Bucket/_/logs-bucket", http.StatusOK)
+	if _, ok := body["compositionSelection"]; ok {
+		t.Errorf("got a composition selection for a managed resource")
+	}
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gravitek/crossplane-spy/internal/history"
+	"github.com/gravitek/crossplane-spy/internal/k8s"
+	"github.com/gravitek/crossplane-spy/internal/models"
+)
+
+// getResourceHistory returns the condition transitions observed on a resource and the Events about it, oldest first
+// The history is kept after the resource is deleted, ?type= restricts it to one condition type
+func getResourceHistory(client k8s.ResourceReader, store history.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := context.Background()
+
+		gvr, namespace, ok := resolveKind(ctx, c, client)
+		if !ok {
+			return
+		}
+
+		gvk, err := client.KindFor(gvr)
+		if err != nil {
+			log.Printf("Error resolving kind of %v: %v", gvr, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve kind"})
+			return
+		}
+
+		key := history.ObjectKey{
+			Group:     gvk.Group,
+			Kind:      gvk.Kind,
+			Namespace: namespace,
+			Name:      c.Param("name"),
+		}
+		transitions, err := store.Transitions(key)
+		if err != nil {
+			log.Printf("Error reading history of %v: %v", key, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read resource history"})
+			return
+		}
+
+		if conditionType := c.Query("type"); conditionType != "" {
+			filtered := make([]models.ConditionTransition, 0, len(transitions))
+			for _, t := range transitions {
+				if t.Type == conditionType {
+					filtered = append(filtered, t)
+				}
+			}
+			transitions = filtered
+		}
+
+		events, err := store.Events(key)
+		if err != nil {
+			log.Printf("Error reading events of %v: %v", key, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read resource history"})
+			return
+		}
+
+		c.JSON(http.StatusOK, models.ResourceHistory{
+			Group:       key.Group,
+			Kind:        key.Kind,
+			Namespace:   key.Namespace,
+			Name:        key.Name,
+			Count:       len(transitions),
+			Transitions: transitions,
+			Events:      events,
+		})
+	}
+}
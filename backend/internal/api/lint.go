@@ -0,0 +1,47 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gravitek/crossplane-spy/internal/k8s"
+	"github.com/gravitek/crossplane-spy/internal/report"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// xrdV2Resource is only served by Crossplane v2
+const xrdV2Resource = "compositeresourcedefinitions.v2.apiextensions.crossplane.io"
+
+// getCompositionLint runs static checks against a Composition, using the XRDs and Functions of the object cache
+func getCompositionLint(client k8s.ResourceReader, objectCache *k8s.ObjectCache) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := context.Background()
+		name := c.Param("name")
+
+		composition, err := client.GetResource(ctx, k8s.CompositionGVR, "", name)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Composition %q not found", name)})
+				return
+			}
+			log.Printf("Error getting composition %s: %v", name, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get composition"})
+			return
+		}
+
+		r := report.LintComposition(composition, objectCache.Objects(k8s.CategoryXRD), objectCache.Objects(k8s.CategoryFunction),
+			isCrossplaneV2(ctx, client))
+		r.CacheSynced = objectCache.HasSynced()
+
+		c.JSON(http.StatusOK, r)
+	}
+}
+
+// isCrossplaneV2 reports whether the cluster serves the v2 XRD API
+func isCrossplaneV2(ctx context.Context, client k8s.ResourceReader) bool {
+	gvr, _, err := client.ResolveResource(ctx, xrdV2Resource)
+	return err == nil && gvr.Version == "v2"
+}
@@ -0,0 +1,209 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gravitek/crossplane-spy/internal/k8s"
+	"github.com/gravitek/crossplane-spy/internal/models"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// defaultTailLines is the number of log lines returned per pod when ?tailLines= is not set
+const defaultTailLines = 100
+
+// maxLogLineSize bounds the size of a log line, longer lines end the stream of their pod
+const maxLogLineSize = 1 << 20
+
+// podLogStream is an open log stream of a pod container
+type podLogStream struct {
+	pod       string
+	container string
+	stream    io.ReadCloser
+}
+
+// getPackageLogs streams the logs of the pods of the current revision of a Provider or Function
+// as server-sent "log" events, followed by an "end" event once every stream is over
+// ?tailLines= (default 100) and ?sinceSeconds= limit the past lines, ?container= selects the container
+// (default the package runtime container), ?follow=true keeps streaming new lines until the client disconnects
+func getPackageLogs(client k8s.ResourceReader, gvr schema.GroupVersionResource) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		opts, err := parseLogOptions(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		pkg, ok := fetchPackage(ctx, c, client, gvr)
+		if !ok {
+			return
+		}
+
+		deployment, err := packageDeployment(ctx, client, pkg)
+		if err != nil {
+			log.Printf("Error listing deployments of %s: %v", pkg.GetName(), err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get package deployment"})
+			return
+		}
+		if deployment == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("No deployment found for %s %q", pkg.GetKind(), pkg.GetName())})
+			return
+		}
+		pods, err := deploymentPods(ctx, client, deployment)
+		if err != nil {
+			log.Printf("Error listing pods of deployment %s/%s: %v", deployment.Namespace, deployment.Name, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list package pods"})
+			return
+		}
+		if len(pods) == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("No pods found for deployment %s/%s", deployment.Namespace, deployment.Name)})
+			return
+		}
+
+		// Streams are opened before responding, so that failures are still reported with a status code
+		var streams []podLogStream
+		for i := range pods {
+			podOpts := *opts
+			podOpts.Container = logContainer(&pods[i], c.Query("container"))
+			stream, err := client.StreamPodLogs(ctx, pods[i].Namespace, pods[i].Name, &podOpts)
+			if errors.Is(err, k8s.ErrLogsUnavailable) {
+				closeStreams(streams)
+				c.JSON(http.StatusNotImplemented, gin.H{"error": err.Error()})
+				return
+			}
+			if err != nil {
+				log.Printf("Error streaming logs of pod %s/%s: %v", pods[i].Namespace, pods[i].Name, err)
+				continue
+			}
+			streams = append(streams, podLogStream{pod: pods[i].Name, container: podOpts.Container, stream: stream})
+		}
+		if len(streams) == 0 {
+			c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to stream pod logs"})
+			return
+		}
+
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Header("X-Accel-Buffering", "no")
+		c.Status(http.StatusOK)
+
+		// A followed stream outlives the write timeout of the server
+		if opts.Follow {
+			if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+				log.Printf("Error clearing write deadline of log stream: %v", err)
+			}
+		}
+
+		for line := range readLogStreams(ctx, streams) {
+			c.SSEvent("log", line)
+			c.Writer.Flush()
+		}
+		if ctx.Err() == nil {
+			c.SSEvent("end", gin.H{})
+			c.Writer.Flush()
+		}
+	}
+}
+
+// closeStreams closes the streams opened before the handler returned early
+func closeStreams(streams []podLogStream) {
+	for _, s := range streams {
+		s.stream.Close()
+	}
+}
+
+// readLogStreams reads the lines of all streams concurrently, the returned channel is closed
+// once every stream is over or ctx is done
+func readLogStreams(ctx context.Context, streams []podLogStream) <-chan models.LogLine {
+	lines := make(chan models.LogLine)
+	var wg sync.WaitGroup
+
+	for _, s := range streams {
+		wg.Add(1)
+		go func(s podLogStream) {
+			defer wg.Done()
+			defer s.stream.Close()
+
+			scanner := bufio.NewScanner(s.stream)
+			scanner.Buffer(make([]byte, 0, 64*1024), maxLogLineSize)
+			for scanner.Scan() {
+				select {
+				case lines <- models.LogLine{Pod: s.pod, Container: s.container, Line: scanner.Text()}:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if err := scanner.Err(); err != nil && ctx.Err() == nil {
+				log.Printf("Error reading logs of pod %s: %v", s.pod, err)
+			}
+		}(s)
+	}
+
+	go func() {
+		wg.Wait()
+		close(lines)
+	}()
+	return lines
+}
+
+// parseLogOptions reads the tailLines, sinceSeconds and follow query parameters
+func parseLogOptions(c *gin.Context) (*corev1.PodLogOptions, error) {
+	opts := &corev1.PodLogOptions{}
+
+	tailLines := int64(defaultTailLines)
+	if raw := c.Query("tailLines"); raw != "" {
+		value, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || value < 0 {
+			return nil, fmt.Errorf("invalid tailLines: must be a non-negative integer")
+		}
+		tailLines = value
+	}
+	opts.TailLines = &tailLines
+
+	if raw := c.Query("sinceSeconds"); raw != "" {
+		value, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || value <= 0 {
+			return nil, fmt.Errorf("invalid sinceSeconds: must be a positive integer")
+		}
+		opts.SinceSeconds = &value
+	}
+
+	if raw := c.Query("follow"); raw != "" {
+		follow, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid follow: must be true or false")
+		}
+		opts.Follow = follow
+	}
+	return opts, nil
+}
+
+// logContainer returns the container to read the logs of: the requested one,
+// else the package runtime container, else the first container of the pod
+func logContainer(pod *corev1.Pod, requested string) string {
+	if requested != "" {
+		return requested
+	}
+	for _, container := range pod.Spec.Containers {
+		if container.Name == packageRuntimeContainer {
+			return container.Name
+		}
+	}
+	if len(pod.Spec.Containers) > 0 {
+		return pod.Spec.Containers[0].Name
+	}
+	return ""
+}
@@ -0,0 +1,220 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gravitek/crossplane-spy/internal/models"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Supported sort keys, prefix with "-" for descending order
+const (
+	sortByName      = "name"
+	sortByAge       = "age"
+	sortByNamespace = "namespace"
+	sortByReady     = "ready"
+)
+
+// pageRequest holds the pagination and sorting query parameters
+type pageRequest struct {
+	Limit      int64
+	Sort       string
+	Descending bool
+	cursor     pageCursor
+}
+
+// pageCursor is the decoded form of the opaque continue token returned to clients
+// It either wraps a Kubernetes continue token (paging done by the API server)
+// or the sort key of the last returned item (paging done in-process), so that
+// objects added or removed between requests do not shift the next page
+type pageCursor struct {
+	Continue string   `json:"c,omitempty"`
+	After    *pageKey `json:"a,omitempty"`
+}
+
+// pageKey is the position of a resource in a sorted listing
+// Value is the sort value, empty when sorting by name
+type pageKey struct {
+	Value     string `json:"v,omitempty"`
+	Namespace string `json:"ns,omitempty"`
+	Name      string `json:"n"`
+	Kind      string `json:"k,omitempty"`
+}
+
+// parseListQuery reads the filtering, pagination and sorting query parameters
+func parseListQuery(c *gin.Context) (listFilter, pageRequest, error) {
+	filter, err := parseListFilter(c)
+	if err != nil {
+		return filter, pageRequest{}, err
+	}
+
+	page, err := parsePageRequest(c)
+	return filter, page, err
+}
+
+// parsePageRequest reads and validates the limit, continue and sort query parameters
+func parsePageRequest(c *gin.Context) (pageRequest, error) {
+	var p pageRequest
+
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || limit < 0 {
+			return p, fmt.Errorf("invalid limit: must be a non-negative integer")
+		}
+		p.Limit = limit
+	}
+
+	if raw := c.Query("continue"); raw != "" {
+		data, err := base64.RawURLEncoding.DecodeString(raw)
+		if err != nil {
+			return p, fmt.Errorf("invalid continue token")
+		}
+		if err := json.Unmarshal(data, &p.cursor); err != nil {
+			return p, fmt.Errorf("invalid continue token")
+		}
+	}
+
+	p.Sort = c.Query("sort")
+	if strings.HasPrefix(p.Sort, "-") {
+		p.Sort = strings.TrimPrefix(p.Sort, "-")
+		p.Descending = true
+	}
+	switch p.Sort {
+	case "", sortByName, sortByAge, sortByNamespace, sortByReady:
+	default:
+		return p, fmt.Errorf("invalid sort: must be one of name, age, namespace, ready")
+	}
+
+	return p, nil
+}
+
+// listOptions returns the options for listing a single resource type
+// When no in-process filtering or custom ordering is needed, paging is delegated
+// to the API server, which already returns items ordered by name
+func (p pageRequest) listOptions(filter listFilter) (metav1.ListOptions, bool, error) {
+	opts := filter.ListOptions()
+
+	live := !filter.inProcess() && !p.Descending && (p.Sort == "" || p.Sort == sortByName) && p.cursor.After == nil
+	if !live {
+		if p.cursor.Continue != "" {
+			return opts, false, fmt.Errorf("continue token does not match the requested filters or sort order")
+		}
+		return opts, false, nil
+	}
+
+	opts.Limit = p.Limit
+	opts.Continue = p.cursor.Continue
+	return opts, true, nil
+}
+
+// pageList returns the requested page of a single resource type listing
+// live reports whether the list was already paged by the API server
+func pageList[T models.Resource](items []T, list *unstructured.UnstructuredList, p pageRequest, live bool) ([]T, string) {
+	if !live {
+		return paginate(items, p)
+	}
+	if list.GetContinue() == "" {
+		return items, ""
+	}
+	return items, encodeCursor(pageCursor{Continue: list.GetContinue()})
+}
+
+// paginate sorts the resources and returns the requested page with the token for the next one
+func paginate[T models.Resource](items []T, p pageRequest) ([]T, string) {
+	sortResources(items, p.Sort, p.Descending)
+
+	start := 0
+	if after := p.cursor.After; after != nil {
+		start = sort.Search(len(items), func(i int) bool {
+			cmp := compareKeys(resourceKey(items[i], p.Sort), *after)
+			if p.Descending {
+				return cmp < 0
+			}
+			return cmp > 0
+		})
+	}
+	end := len(items)
+	if p.Limit > 0 && int64(end-start) > p.Limit {
+		end = start + int(p.Limit)
+	}
+
+	next := ""
+	if end < len(items) {
+		last := resourceKey(items[end-1], p.Sort)
+		next = encodeCursor(pageCursor{After: &last})
+	}
+	return items[start:end], next
+}
+
+// sortResources orders resources by the given key, ties are broken by name, namespace and kind
+func sortResources[T models.Resource](items []T, key string, descending bool) {
+	sort.SliceStable(items, func(i, j int) bool {
+		cmp := compareKeys(resourceKey(items[i], key), resourceKey(items[j], key))
+		if descending {
+			return cmp > 0
+		}
+		return cmp < 0
+	})
+}
+
+// resourceKey returns the position of a resource when sorting by the given key
+func resourceKey[T models.Resource](item T, key string) pageKey {
+	base := item.GetBaseResource()
+	k := pageKey{Namespace: base.Metadata.Namespace, Name: base.Metadata.Name, Kind: base.Kind}
+
+	switch key {
+	case sortByAge:
+		// Oldest first, like kubectl --sort-by=.metadata.creationTimestamp
+		// The fixed width format orders like the timestamps
+		k.Value = base.Metadata.CreationTimestamp.UTC().Format(sortTimeFormat)
+	case sortByNamespace:
+		k.Value = base.Metadata.Namespace
+	case sortByReady:
+		// Not ready first, so problems surface at the top
+		k.Value = strconv.FormatBool(item.GetResourceStatus().Ready)
+	}
+	return k
+}
+
+// sortTimeFormat formats creation timestamps as sort values
+const sortTimeFormat = "20060102150405.000000000"
+
+// compareKeys compares the positions of two resources
+func compareKeys(a, b pageKey) int {
+	if cmp := strings.Compare(a.Value, b.Value); cmp != 0 {
+		return cmp
+	}
+	if cmp := strings.Compare(a.Name, b.Name); cmp != 0 {
+		return cmp
+	}
+	if cmp := strings.Compare(a.Namespace, b.Namespace); cmp != 0 {
+		return cmp
+	}
+	return strings.Compare(a.Kind, b.Kind)
+}
+
+// encodeCursor returns the opaque continue token for a cursor
+func encodeCursor(cursor pageCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// listResponse builds the JSON body of a list endpoint
+func listResponse(kind string, items interface{}, count int, next string) gin.H {
+	response := gin.H{
+		"kind":  kind,
+		"count": count,
+		"items": items,
+	}
+	if next != "" {
+		response["continue"] = next
+	}
+	return response
+}
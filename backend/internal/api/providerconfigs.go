@@ -0,0 +1,81 @@
+package api
+
+import (
+	"context"
+	"log"
+
+	"github.com/gravitek/crossplane-spy/internal/k8s"
+	"github.com/gravitek/crossplane-spy/internal/models"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// maxUsagesPerConfig bounds the ProviderConfigUsages listed per ProviderConfig, status.users has the total
+const maxUsagesPerConfig = 100
+
+// providerConfigKey identifies a ProviderConfig referenced by a ProviderConfigUsage
+type providerConfigKey struct {
+	group     string
+	namespace string
+	name      string
+}
+
+// attachProviderConfigUsages sets the ProviderConfigUsages of each config
+// Providers serve ProviderConfigUsages in the group and version of their ProviderConfigs
+func attachProviderConfigUsages(ctx context.Context, client k8s.ResourceReader, configs []models.ProviderConfig) {
+	usages := make(map[providerConfigKey][]models.ProviderConfigUsage)
+	listed := make(map[schema.GroupVersion]bool)
+
+	for _, config := range configs {
+		gv, err := schema.ParseGroupVersion(config.APIVersion)
+		if err != nil || listed[gv] {
+			continue
+		}
+		listed[gv] = true
+
+		list, err := client.ListResources(ctx, gv.WithResource("providerconfigusages"), "", metav1.ListOptions{})
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				log.Printf("Error listing provider config usages for %v: %v", gv, err)
+			}
+			continue
+		}
+		for _, item := range list.Items {
+			key, usage, ok := convertProviderConfigUsage(&item)
+			if ok && len(usages[key]) < maxUsagesPerConfig {
+				usages[key] = append(usages[key], usage)
+			}
+		}
+	}
+
+	for i, config := range configs {
+		group := schema.FromAPIVersionAndKind(config.APIVersion, config.Kind).Group
+		configs[i].Usages = usages[providerConfigKey{group, config.Metadata.Namespace, config.Metadata.Name}]
+	}
+}
+
+// convertProviderConfigUsage returns the config referenced by a ProviderConfigUsage and the resource using it
+// The references are top-level fields of the usage, namespaced usages reference the ProviderConfig of their
+// namespace unless they reference a ClusterProviderConfig
+func convertProviderConfigUsage(obj *unstructured.Unstructured) (providerConfigKey, models.ProviderConfigUsage, bool) {
+	name, _, _ := unstructured.NestedString(obj.Object, "providerConfigRef", "name")
+	if name == "" {
+		return providerConfigKey{}, models.ProviderConfigUsage{}, false
+	}
+	key := providerConfigKey{group: obj.GroupVersionKind().Group, name: name}
+	if kind, _, _ := unstructured.NestedString(obj.Object, "providerConfigRef", "kind"); kind != "ClusterProviderConfig" {
+		key.namespace = obj.GetNamespace()
+	}
+
+	usage := models.ProviderConfigUsage{Name: obj.GetName()}
+	usage.Resource.APIVersion, _, _ = unstructured.NestedString(obj.Object, "resourceRef", "apiVersion")
+	usage.Resource.Kind, _, _ = unstructured.NestedString(obj.Object, "resourceRef", "kind")
+	usage.Resource.Name, _, _ = unstructured.NestedString(obj.Object, "resourceRef", "name")
+	usage.Resource.Namespace, _, _ = unstructured.NestedString(obj.Object, "resourceRef", "namespace")
+	if usage.Resource.Namespace == "" {
+		usage.Resource.Namespace = obj.GetNamespace()
+	}
+	return key, usage, true
+}
@@ -0,0 +1,197 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gravitek/crossplane-spy/internal/k8s"
+	"github.com/gravitek/crossplane-spy/internal/models"
+	"github.com/gravitek/crossplane-spy/internal/report"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// getSyncFailuresReport groups the managed resources failing to sync by provider, kind and error message,
+// and lists the ready ones whose spec keeps being updated
+// ?provider= and ?kind= restrict the report, ?minUpdates= sets the drift threshold
+func getSyncFailuresReport(client k8s.ResourceReader, objectCache *k8s.ObjectCache, churn *report.ChurnTracker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := context.Background()
+
+		minUpdates, err := parsePositiveInt(c, "minUpdates", report.DefaultMinUpdates)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		r := report.SyncFailures(objectCache.Objects(k8s.CategoryManaged), providerIndex(ctx, client), churn, report.SyncFailureOptions{
+			Provider:   c.Query("provider"),
+			Kind:       c.Query("kind"),
+			MinUpdates: minUpdates,
+		})
+		r.CacheSynced = objectCache.HasSynced()
+
+		c.JSON(http.StatusOK, r)
+	}
+}
+
+// getStuckDeletionsReport lists the objects being deleted with their finalizers and the Usages blocking them
+// ?minAge= (e.g. 10m) skips recent deletions, ?category= restricts the report to one object cache category
+func getStuckDeletionsReport(objectCache *k8s.ObjectCache) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		minAge, err := parseDuration(c, "minAge")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		r := report.StuckDeletions(objectCache.Objects(), report.NewUsageIndex(objectCache.Objects(k8s.CategoryUsage)), report.StuckDeletionOptions{
+			MinAge:   minAge,
+			Category: c.Query("category"),
+		})
+		r.CacheSynced = objectCache.HasSynced()
+
+		c.JSON(http.StatusOK, r)
+	}
+}
+
+// getPausedReport lists the objects whose reconciliation is paused, with who paused them and when
+// ?minAge= (e.g. 24h) skips recent pauses, ?category= restricts the report to one object cache category
+func getPausedReport(objectCache *k8s.ObjectCache) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		minAge, err := parseDuration(c, "minAge")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		r := report.Paused(objectCache.Objects(), report.PausedOptions{
+			MinAge:   minAge,
+			Category: c.Query("category"),
+		})
+		r.CacheSynced = objectCache.HasSynced()
+
+		c.JSON(http.StatusOK, r)
+	}
+}
+
+// getPolicyAuditReport counts the managed resources per provider, namespace, management and deletion policies,
+// and flags risky combinations
+// ?provider= and ?namespace= restrict the audit, ?protectedSelector= selects the resources that must not be deleted
+func getPolicyAuditReport(client k8s.ResourceReader, objectCache *k8s.ObjectCache) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := context.Background()
+
+		protected, err := labels.Parse(c.DefaultQuery("protectedSelector", report.DefaultProtectedSelector))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid protectedSelector: %v", err)})
+			return
+		}
+
+		r := report.PolicyAudit(objectCache.Objects(k8s.CategoryManaged), providerIndex(ctx, client), report.PolicyAuditOptions{
+			Provider:  c.Query("provider"),
+			Namespace: c.Query("namespace"),
+			Protected: protected,
+		})
+		r.CacheSynced = objectCache.HasSynced()
+
+		c.JSON(http.StatusOK, r)
+	}
+}
+
+// getOrphansReport lists the managed resources whose owning composite resource is missing or who have no owner,
+// and the composite resources whose resourceRefs point to missing objects
+func getOrphansReport(client k8s.ResourceReader, objectCache *k8s.ObjectCache) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := context.Background()
+
+		resolver := newObjectResolver(client, objectCache)
+		r := report.Orphans(objectCache.Objects(k8s.CategoryManaged), objectCache.Objects(k8s.CategoryComposite), func(ref models.ObjectRef) *unstructured.Unstructured {
+			return resolver.find(ctx, ref)
+		})
+		r.CacheSynced = objectCache.HasSynced()
+
+		c.JSON(http.StatusOK, r)
+	}
+}
+
+// upgradePackageResources are the types of the upgrade report the object cache does not watch,
+// resolved through discovery since Crossplane v2 no longer serves ControllerConfigs
+var upgradePackageResources = []string{"controllerconfigs.pkg.crossplane.io", "configurations.pkg.crossplane.io"}
+
+// getUpgradeReport inventories the Crossplane v1 constructs deprecated or removed in Crossplane v2, grouped by object,
+// with the changes required before upgrading
+func getUpgradeReport(client k8s.ResourceReader, objectCache *k8s.ObjectCache) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := context.Background()
+
+		var packages []unstructured.Unstructured
+		for _, resource := range upgradePackageResources {
+			gvr, _, err := client.ResolveResource(ctx, resource)
+			if err != nil {
+				if meta.IsNoMatchError(err) {
+					continue
+				}
+				log.Printf("Error resolving %s: %v", resource, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build upgrade report"})
+				return
+			}
+			list, err := client.ListResources(ctx, gvr, "", metav1.ListOptions{})
+			if err != nil {
+				log.Printf("Error listing %s: %v", resource, err)
+				c.JSON(listErrorStatus(err), gin.H{"error": "Failed to build upgrade report"})
+				return
+			}
+			packages = append(packages, list.Items...)
+		}
+
+		r := report.V2Upgrade(objectCache.Objects(), packages)
+		r.CacheSynced = objectCache.HasSynced()
+
+		c.JSON(http.StatusOK, r)
+	}
+}
+
+// providerIndex maps the managed resource CRDs to their provider from the ProviderRevisions
+// When revisions cannot be listed, resources are attributed to their API group
+func providerIndex(ctx context.Context, client k8s.ResourceReader) report.ProviderIndex {
+	revisions, err := client.ListProviderRevisions(ctx, metav1.ListOptions{})
+	if err != nil {
+		log.Printf("Error listing provider revisions, reporting API groups instead of providers: %v", err)
+		return report.NewProviderIndex(nil)
+	}
+	return report.NewProviderIndex(revisions.Items)
+}
+
+// parsePositiveInt parses an optional positive integer query parameter
+func parsePositiveInt(c *gin.Context, name string, defaultValue int) (int, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return defaultValue, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 1 {
+		return 0, fmt.Errorf("invalid %s: must be a positive integer", name)
+	}
+	return value, nil
+}
+
+// parseDuration parses an optional non-negative duration query parameter, zero when absent
+func parseDuration(c *gin.Context, name string) (time.Duration, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return 0, nil
+	}
+	value, err := time.ParseDuration(raw)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid %s: must be a duration such as 10m", name)
+	}
+	return value, nil
+}
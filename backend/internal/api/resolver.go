@@ -0,0 +1,74 @@
+package api
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/gravitek/crossplane-spy/internal/k8s"
+	"github.com/gravitek/crossplane-spy/internal/models"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// objectResolver looks up referenced objects regardless of their API version
+// Cached Crossplane objects are looked up in memory, other objects are fetched from the API server once
+type objectResolver struct {
+	client  k8s.ResourceReader
+	objects map[objectKey]*unstructured.Unstructured
+}
+
+// objectKey identifies an object regardless of its API version
+type objectKey struct {
+	groupKind schema.GroupKind
+	namespace string
+	name      string
+}
+
+// newObjectResolver indexes the objects of the object cache
+func newObjectResolver(client k8s.ResourceReader, objectCache *k8s.ObjectCache) *objectResolver {
+	r := &objectResolver{client: client, objects: make(map[objectKey]*unstructured.Unstructured)}
+	for _, cached := range objectCache.Objects() {
+		obj := cached.Object
+		r.objects[objectKey{obj.GroupVersionKind().GroupKind(), obj.GetNamespace(), obj.GetName()}] = obj
+	}
+	return r
+}
+
+// find returns the referenced object, nil when it does not exist
+func (r *objectResolver) find(ctx context.Context, ref models.ObjectRef) *unstructured.Unstructured {
+	gk := schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind).GroupKind()
+	key := objectKey{gk, ref.Namespace, ref.Name}
+	obj, ok := r.objects[key]
+	if !ok {
+		obj = r.get(ctx, gk, ref.Namespace, ref.Name)
+		r.objects[key] = obj
+	}
+	return obj
+}
+
+// get fetches an object that is not in the object cache, nil when it does not exist
+func (r *objectResolver) get(ctx context.Context, gk schema.GroupKind, namespace, name string) *unstructured.Unstructured {
+	kind := strings.ToLower(gk.Kind)
+	if gk.Group != "" {
+		kind += "." + gk.Group
+	}
+
+	gvr, clusterScoped, err := r.client.ResolveResource(ctx, kind)
+	if err != nil {
+		return nil
+	}
+	if clusterScoped {
+		namespace = ""
+	}
+
+	obj, err := r.client.GetResource(ctx, gvr, namespace, name)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			log.Printf("Error getting %v %s/%s: %v", gvr, namespace, name, err)
+		}
+		return nil
+	}
+	return obj
+}
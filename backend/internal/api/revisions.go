@@ -0,0 +1,87 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gravitek/crossplane-spy/internal/k8s"
+	"github.com/gravitek/crossplane-spy/internal/models"
+	"github.com/gravitek/crossplane-spy/internal/report"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// getCompositionRevisions returns the revision history of a Composition, latest first,
+// each revision with the diff of its pipeline, resources and spec against the previous one
+func getCompositionRevisions(client k8s.ResourceReader) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := context.Background()
+		name := c.Param("name")
+
+		if _, err := client.GetResource(ctx, k8s.CompositionGVR, "", name); err != nil {
+			if apierrors.IsNotFound(err) {
+				c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Composition %q not found", name)})
+				return
+			}
+			log.Printf("Error getting composition %s: %v", name, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get composition"})
+			return
+		}
+
+		revisions, err := client.ListResources(ctx, k8s.CompositionRevisionGVR, "", metav1.ListOptions{
+			LabelSelector: models.CompositionNameLabel + "=" + name,
+		})
+		if err != nil {
+			log.Printf("Error listing composition revisions of %s: %v", name, err)
+			c.JSON(listErrorStatus(err), gin.H{"error": "Failed to list composition revisions"})
+			return
+		}
+
+		history := report.RevisionHistory(revisions.Items)
+		c.JSON(http.StatusOK, listResponse("CompositionRevisionList", history, len(history), ""))
+	}
+}
+
+// markRevisionLag sets the latest revision of the Composition of each composite resource,
+// and whether the composite resource uses an older revision
+func markRevisionLag(ctx context.Context, client k8s.ResourceReader, xrs []models.CompositeResource) {
+	compositions := sets.New[string]()
+	for _, xr := range xrs {
+		if xr.Spec.CompositionRef != nil && xr.Spec.CompositionRevisionRef != nil {
+			compositions.Insert(xr.Spec.CompositionRef.Name)
+		}
+	}
+	if compositions.Len() == 0 {
+		return
+	}
+
+	// Only the revisions of the referenced Compositions are listed
+	requirement, err := labels.NewRequirement(models.CompositionNameLabel, selection.In, sets.List(compositions))
+	if err != nil {
+		log.Printf("Error selecting composition revisions: %v", err)
+		return
+	}
+	revisions, err := client.ListResources(ctx, k8s.CompositionRevisionGVR, "", metav1.ListOptions{
+		LabelSelector: labels.NewSelector().Add(*requirement).String(),
+	})
+	if err != nil {
+		log.Printf("Error listing composition revisions: %v", err)
+		return
+	}
+	latest := report.LatestRevisions(revisions.Items)
+
+	for i := range xrs {
+		spec := xrs[i].Spec
+		if spec.CompositionRef == nil || spec.CompositionRevisionRef == nil {
+			continue
+		}
+		xrs[i].Status.LatestRevision = latest[spec.CompositionRef.Name]
+		xrs[i].Status.RevisionLagging = xrs[i].Status.LatestRevision != "" && xrs[i].Status.LatestRevision != spec.CompositionRevisionRef.Name
+	}
+}
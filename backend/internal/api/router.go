@@ -4,11 +4,14 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gravitek/crossplane-spy/internal/history"
 	"github.com/gravitek/crossplane-spy/internal/k8s"
+	"github.com/gravitek/crossplane-spy/internal/report"
+	"github.com/gravitek/crossplane-spy/internal/snapshot"
 )
 
 // NewRouter creates and configures the API router
-func NewRouter(k8sClient *k8s.Client) *gin.Engine {
+func NewRouter(k8sClient k8s.ResourceReader, objectCache *k8s.ObjectCache, historyStore history.Store, snapshotStore snapshot.Store, churn *report.ChurnTracker) *gin.Engine {
 	router := gin.Default()
 
 	// CORS middleware for Next.js frontend
@@ -26,19 +29,49 @@ func NewRouter(k8sClient *k8s.Client) *gin.Engine {
 		// Resource endpoints
 		v1.GET("/resources", getResources(k8sClient))
 		v1.GET("/resources/:kind", getResourcesByKind(k8sClient))
-		v1.GET("/resources/:kind/:namespace/:name", getResource(k8sClient))
+		v1.GET("/resources/:kind/:namespace/:name", getResource(k8sClient, objectCache))
+		v1.GET("/resources/:kind/:namespace/:name/raw", exportResource(k8sClient))
+		v1.GET("/resources/:kind/:namespace/:name/history", getResourceHistory(k8sClient, historyStore))
 
 		// Specific resource type endpoints
 		v1.GET("/providers", getProviders(k8sClient))
+		v1.GET("/providers/:name", getProvider(k8sClient))
+		v1.GET("/providers/:name/logs", getPackageLogs(k8sClient, k8s.ProviderGVR))
 		v1.GET("/providerconfigs", getProviderConfigs(k8sClient))
 		v1.GET("/xrds", getXRDs(k8sClient))
 		v1.GET("/compositions", getCompositions(k8sClient))
+		v1.GET("/compositions/:name", getComposition(k8sClient))
+		v1.GET("/compositions/:name/revisions", getCompositionRevisions(k8sClient))
+		v1.GET("/compositions/:name/lint", getCompositionLint(k8sClient, objectCache))
+		v1.GET("/environmentconfigs", getEnvironmentConfigs(k8sClient))
+		v1.GET("/environmentconfigs/:name", getEnvironmentConfig(k8sClient))
 		v1.GET("/xrs", getXRs(k8sClient))
 		v1.GET("/functions", getFunctions(k8sClient))
+		v1.GET("/functions/:name", getFunction(k8sClient))
+		v1.GET("/functions/:name/logs", getPackageLogs(k8sClient, k8s.FunctionGVR))
+		v1.GET("/usages", getUsages(k8sClient, objectCache))
 
 		// Scope-based endpoints (cluster vs namespace)
 		v1.GET("/cluster-resources", getClusterResources(k8sClient))
 		v1.GET("/namespace-resources", getNamespaceResources(k8sClient))
+
+		// Full-text search across all cached Crossplane objects
+		v1.GET("/search", searchResources(objectCache))
+
+		// Operational reports built from the object cache
+		v1.GET("/reports/sync-failures", getSyncFailuresReport(k8sClient, objectCache, churn))
+		v1.GET("/reports/stuck-deletions", getStuckDeletionsReport(objectCache))
+		v1.GET("/reports/orphans", getOrphansReport(k8sClient, objectCache))
+		v1.GET("/reports/paused", getPausedReport(objectCache))
+		v1.GET("/reports/policies", getPolicyAuditReport(k8sClient, objectCache))
+		v1.GET("/reports/v2-upgrade", getUpgradeReport(k8sClient, objectCache))
+
+		// Snapshots of all Crossplane objects and diffs between them
+		v1.GET("/snapshots", listSnapshots(snapshotStore))
+		v1.POST("/snapshots", createSnapshot(objectCache, snapshotStore))
+		v1.GET("/snapshots/diff", diffSnapshots(objectCache, snapshotStore))
+		v1.GET("/snapshots/:id", getSnapshot(snapshotStore))
+		v1.DELETE("/snapshots/:id", deleteSnapshot(snapshotStore))
 	}
 
 	// Serve frontend static files (only in production/Docker)
@@ -0,0 +1,217 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gravitek/crossplane-spy/internal/k8s"
+	"github.com/gravitek/crossplane-spy/internal/models"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// revisionLabel is set by Crossplane on the Deployment of a package revision, with the name of the revision
+// The pkg.crossplane.io/provider and pkg.crossplane.io/function labels hold the name in the package metadata,
+// which differs from the name of the Provider or Function object
+const revisionLabel = "pkg.crossplane.io/revision"
+
+// packageRuntimeContainer is the name of the container running a package in its Deployment
+const packageRuntimeContainer = "package-runtime"
+
+// defaultRuntimeConfig is the DeploymentRuntimeConfig used by packages without runtimeConfigRef
+const defaultRuntimeConfig = "default"
+
+// getProvider returns a Provider with its DeploymentRuntimeConfig, Deployment and pods
+func getProvider(client k8s.ResourceReader) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := context.Background()
+
+		pkg, ok := fetchPackage(ctx, c, client, k8s.ProviderGVR)
+		if !ok {
+			return
+		}
+
+		c.JSON(http.StatusOK, models.ProviderDetail{
+			Provider: convertToProviders([]unstructured.Unstructured{*pkg})[0],
+			Runtime:  packageRuntime(ctx, client, pkg),
+		})
+	}
+}
+
+// getFunction returns a Function with its DeploymentRuntimeConfig, Deployment and pods
+func getFunction(client k8s.ResourceReader) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := context.Background()
+
+		pkg, ok := fetchPackage(ctx, c, client, k8s.FunctionGVR)
+		if !ok {
+			return
+		}
+
+		c.JSON(http.StatusOK, models.FunctionDetail{
+			Function: convertToFunctions([]unstructured.Unstructured{*pkg})[0],
+			Runtime:  packageRuntime(ctx, client, pkg),
+		})
+	}
+}
+
+// fetchPackage gets the package named by the :name path parameter
+// On failure the error response is written and false is returned
+func fetchPackage(ctx context.Context, c *gin.Context, client k8s.ResourceReader, gvr schema.GroupVersionResource) (*unstructured.Unstructured, bool) {
+	name := c.Param("name")
+
+	pkg, err := client.GetResource(ctx, gvr, "", name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("%s %q not found", gvr.Resource, name)})
+			return nil, false
+		}
+		log.Printf("Error getting %v %s: %v", gvr, name, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get " + gvr.Resource})
+		return nil, false
+	}
+	return pkg, true
+}
+
+// packageRuntime resolves the DeploymentRuntimeConfig of a package, and finds the Deployment of its current revision
+// and the Deployment pods through the revision label Crossplane sets on them
+func packageRuntime(ctx context.Context, client k8s.ResourceReader, pkg *unstructured.Unstructured) models.PackageRuntime {
+	runtime := models.PackageRuntime{Pods: []models.PodStatus{}}
+
+	runtime.RuntimeConfig.Name, _, _ = unstructured.NestedString(pkg.Object, "spec", "runtimeConfigRef", "name")
+	if runtime.RuntimeConfig.Name == "" {
+		runtime.RuntimeConfig.Name = defaultRuntimeConfig
+	}
+	config, err := client.GetResource(ctx, k8s.DeploymentRuntimeConfigGVR, "", runtime.RuntimeConfig.Name)
+	switch {
+	case err == nil:
+		runtime.RuntimeConfig.Found = true
+		runtime.RuntimeConfig.Spec, _, _ = unstructured.NestedMap(config.Object, "spec")
+	case !apierrors.IsNotFound(err):
+		log.Printf("Error getting deployment runtime config %s: %v", runtime.RuntimeConfig.Name, err)
+	}
+
+	deployment, err := packageDeployment(ctx, client, pkg)
+	if err != nil {
+		log.Printf("Error listing deployments of %s: %v", pkg.GetName(), err)
+		return runtime
+	}
+	if deployment == nil {
+		return runtime
+	}
+	runtime.Deployment = convertDeploymentStatus(deployment)
+
+	pods, err := deploymentPods(ctx, client, deployment)
+	if err != nil {
+		log.Printf("Error listing pods of deployment %s/%s: %v", deployment.Namespace, deployment.Name, err)
+		return runtime
+	}
+	for i := range pods {
+		runtime.Pods = append(runtime.Pods, convertPodStatus(&pods[i]))
+	}
+	return runtime
+}
+
+// packageDeployment returns the Deployment of the current revision of a package, nil when it has none
+func packageDeployment(ctx context.Context, client k8s.ResourceReader, pkg *unstructured.Unstructured) (*appsv1.Deployment, error) {
+	currentRevision, _, _ := unstructured.NestedString(pkg.Object, "status", "currentRevision")
+	if currentRevision == "" {
+		return nil, nil
+	}
+	deployments, err := client.ListDeployments(ctx, "", metav1.ListOptions{LabelSelector: revisionLabel + "=" + currentRevision})
+	if err != nil || len(deployments.Items) == 0 {
+		return nil, err
+	}
+	return &deployments.Items[0], nil
+}
+
+// deploymentPods returns the pods selected by a Deployment
+func deploymentPods(ctx context.Context, client k8s.ResourceReader, deployment *appsv1.Deployment) ([]corev1.Pod, error) {
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil || selector.Empty() {
+		return nil, err
+	}
+	pods, err := client.ListPods(ctx, deployment.Namespace, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	return pods.Items, nil
+}
+
+func convertDeploymentStatus(d *appsv1.Deployment) *models.DeploymentStatus {
+	status := &models.DeploymentStatus{
+		Name:              d.Name,
+		Namespace:         d.Namespace,
+		Image:             packageImage(d.Spec.Template.Spec.Containers),
+		ReadyReplicas:     d.Status.ReadyReplicas,
+		AvailableReplicas: d.Status.AvailableReplicas,
+		UpdatedReplicas:   d.Status.UpdatedReplicas,
+	}
+	if d.Spec.Replicas != nil {
+		status.Replicas = *d.Spec.Replicas
+	}
+	for _, cond := range d.Status.Conditions {
+		status.Conditions = append(status.Conditions, models.Condition{
+			Type:               string(cond.Type),
+			Status:             string(cond.Status),
+			LastTransitionTime: cond.LastTransitionTime.Time,
+			Reason:             cond.Reason,
+			Message:            cond.Message,
+		})
+	}
+	return status
+}
+
+func convertPodStatus(pod *corev1.Pod) models.PodStatus {
+	status := models.PodStatus{
+		Name:  pod.Name,
+		Phase: string(pod.Status.Phase),
+		Image: packageImage(pod.Spec.Containers),
+		Node:  pod.Spec.NodeName,
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			status.Ready = cond.Status == corev1.ConditionTrue
+		}
+	}
+
+	for _, cs := range pod.Status.ContainerStatuses {
+		status.Restarts += cs.RestartCount
+		if cs.Name != packageRuntimeContainer && len(pod.Status.ContainerStatuses) > 1 {
+			continue
+		}
+		if cs.State.Waiting != nil {
+			status.Waiting = cs.State.Waiting.Reason
+			status.WaitingMessage = cs.State.Waiting.Message
+		}
+		if t := cs.LastTerminationState.Terminated; t != nil {
+			status.LastTermination = t.Reason
+			status.LastTerminationMessage = t.Message
+			if !t.FinishedAt.IsZero() {
+				finished := t.FinishedAt.Time
+				status.LastTerminationTime = &finished
+			}
+		}
+	}
+	return status
+}
+
+// packageImage returns the image of the package runtime container, or of the first container
+func packageImage(containers []corev1.Container) string {
+	for _, c := range containers {
+		if c.Name == packageRuntimeContainer {
+			return c.Image
+		}
+	}
+	if len(containers) > 0 {
+		return containers[0].Image
+	}
+	return ""
+}
@@ -0,0 +1,313 @@
+package api
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gravitek/crossplane-spy/internal/k8s"
+	"github.com/gravitek/crossplane-spy/internal/models"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	defaultSearchLimit = 50
+	maxSearchLimit     = 500
+
+	// snippetContext is the number of characters kept around a match in snippets
+	snippetContext = 40
+
+	// forProviderMaxDepth limits how deep spec.forProvider is walked for string values
+	forProviderMaxDepth = 4
+)
+
+// Ranking weights of the searched fields, an exact match of the whole value doubles the weight
+const (
+	weightName         = 100
+	weightExternalName = 80
+	weightSpec         = 30
+	weightLabel        = 20
+	weightCondition    = 15
+	weightAnnotation   = 10
+)
+
+// searchedSpecFields are the spec fields searched on every kind, in addition to spec.forProvider
+var searchedSpecFields = [][]string{
+	{"spec", "package"},
+	{"spec", "group"},
+	{"spec", "names", "kind"},
+	{"spec", "claimNames", "kind"},
+	{"spec", "compositeTypeRef", "kind"},
+	{"spec", "compositionRef", "name"},
+	{"spec", "crossplane", "compositionRef", "name"},
+	{"spec", "providerConfigRef", "name"},
+	{"spec", "writeConnectionSecretToRef", "name"},
+	{"spec", "resourceRef", "name"},
+}
+
+// searchedRefLists are the reference lists whose names are searched
+var searchedRefLists = [][]string{
+	{"spec", "resourceRefs"},
+	{"spec", "crossplane", "resourceRefs"},
+}
+
+// searchField is a candidate field of an object with its ranking weight
+type searchField struct {
+	path   string
+	value  string
+	weight int
+}
+
+// searchResources searches names, labels, annotations, external names, condition messages
+// and selected spec fields across every cached Crossplane object
+func searchResources(objectCache *k8s.ObjectCache) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := strings.TrimSpace(c.Query("q"))
+		if query == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "missing search query: q"})
+			return
+		}
+
+		limit := defaultSearchLimit
+		if raw := c.Query("limit"); raw != "" {
+			l, err := strconv.Atoi(raw)
+			if err != nil || l <= 0 || l > maxSearchLimit {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid limit: must be between 1 and %d", maxSearchLimit)})
+				return
+			}
+			limit = l
+		}
+
+		kind := c.Query("kind")
+		namespace := c.Query("namespace")
+		terms := strings.Fields(strings.ToLower(query))
+
+		var categories []string
+		if category := c.Query("category"); category != "" {
+			categories = []string{category}
+		}
+
+		hits := []models.SearchHit{}
+		for _, cached := range objectCache.Objects(categories...) {
+			obj := cached.Object
+			if kind != "" && !strings.EqualFold(obj.GetKind(), kind) {
+				continue
+			}
+			if namespace != "" && obj.GetNamespace() != namespace {
+				continue
+			}
+
+			score, matches, ok := searchObject(obj, terms)
+			if !ok {
+				continue
+			}
+			hits = append(hits, models.SearchHit{
+				Kind:       obj.GetKind(),
+				APIVersion: obj.GetAPIVersion(),
+				Category:   cached.Category,
+				Name:       obj.GetName(),
+				Namespace:  obj.GetNamespace(),
+				Score:      score,
+				Matches:    matches,
+			})
+		}
+
+		// Best matches first, ties ordered by kind, namespace and name for stable results
+		sort.Slice(hits, func(i, j int) bool {
+			a, b := hits[i], hits[j]
+			if a.Score != b.Score {
+				return a.Score > b.Score
+			}
+			if a.Kind != b.Kind {
+				return a.Kind < b.Kind
+			}
+			if a.Namespace != b.Namespace {
+				return a.Namespace < b.Namespace
+			}
+			return a.Name < b.Name
+		})
+
+		total := len(hits)
+		if len(hits) > limit {
+			hits = hits[:limit]
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"kind":   "SearchResult",
+			"query":  query,
+			"count":  len(hits),
+			"total":  total,
+			"synced": objectCache.HasSynced(),
+			"items":  hits,
+		})
+	}
+}
+
+// searchObject matches every term against the searchable fields of an object
+// An object is a hit only if all terms match, its score is the sum of the best match of each term
+func searchObject(obj *unstructured.Unstructured, terms []string) (int, []models.SearchMatch, bool) {
+	fields := searchFields(obj)
+
+	score := 0
+	var matches []models.SearchMatch
+	matched := make(map[string]bool)
+
+	for _, term := range terms {
+		best := 0
+		for _, field := range fields {
+			lower := strings.ToLower(field.value)
+			idx := strings.Index(lower, term)
+			if idx < 0 {
+				continue
+			}
+
+			// Lowercasing can change the byte length of some runes, snippets then use the lowered value
+			source := field.value
+			if len(lower) != len(source) {
+				source = lower
+			}
+
+			weight := field.weight
+			if lower == term {
+				weight *= 2
+			}
+			if weight > best {
+				best = weight
+			}
+
+			if !matched[field.path] {
+				matched[field.path] = true
+				matches = append(matches, models.SearchMatch{
+					Field:   field.path,
+					Snippet: snippet(source, idx, len(term)),
+				})
+			}
+		}
+		if best == 0 {
+			return 0, nil, false
+		}
+		score += best
+	}
+
+	return score, matches, true
+}
+
+// searchFields extracts the searchable fields of an object
+func searchFields(obj *unstructured.Unstructured) []searchField {
+	fields := []searchField{
+		{path: "metadata.name", value: obj.GetName(), weight: weightName},
+	}
+
+	labels := obj.GetLabels()
+	for _, key := range slices.Sorted(maps.Keys(labels)) {
+		value := labels[key]
+		fields = append(fields, searchField{path: fmt.Sprintf("metadata.labels[%s]", key), value: key + "=" + value, weight: weightLabel})
+	}
+
+	annotations := obj.GetAnnotations()
+	for _, key := range slices.Sorted(maps.Keys(annotations)) {
+		value := annotations[key]
+		weight := weightAnnotation
+		if key == models.ExternalNameAnnotation {
+			weight = weightExternalName
+		}
+		fields = append(fields, searchField{path: fmt.Sprintf("metadata.annotations[%s]", key), value: value, weight: weight})
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		condType, _ := cond["type"].(string)
+		if message, ok := cond["message"].(string); ok && message != "" {
+			fields = append(fields, searchField{path: fmt.Sprintf("status.conditions[%s].message", condType), value: message, weight: weightCondition})
+		}
+		if reason, ok := cond["reason"].(string); ok && reason != "" {
+			fields = append(fields, searchField{path: fmt.Sprintf("status.conditions[%s].reason", condType), value: reason, weight: weightCondition})
+		}
+	}
+
+	for _, path := range searchedSpecFields {
+		if value, found, _ := unstructured.NestedString(obj.Object, path...); found && value != "" {
+			fields = append(fields, searchField{path: strings.Join(path, "."), value: value, weight: weightSpec})
+		}
+	}
+
+	for _, path := range searchedRefLists {
+		refs, _, _ := unstructured.NestedSlice(obj.Object, path...)
+		for i, r := range refs {
+			ref, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if name, ok := ref["name"].(string); ok && name != "" {
+				fields = append(fields, searchField{path: fmt.Sprintf("%s[%d].name", strings.Join(path, "."), i), value: name, weight: weightSpec})
+			}
+		}
+	}
+
+	if forProvider, found, _ := unstructured.NestedMap(obj.Object, "spec", "forProvider"); found {
+		fields = appendStringLeaves(fields, "spec.forProvider", forProvider, forProviderMaxDepth)
+	}
+
+	return fields
+}
+
+// appendStringLeaves appends the string values of a nested map as searchable spec fields
+func appendStringLeaves(fields []searchField, path string, value interface{}, depth int) []searchField {
+	switch v := value.(type) {
+	case string:
+		if v != "" {
+			fields = append(fields, searchField{path: path, value: v, weight: weightSpec})
+		}
+	case map[string]interface{}:
+		if depth == 0 {
+			return fields
+		}
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			fields = appendStringLeaves(fields, path+"."+key, v[key], depth-1)
+		}
+	case []interface{}:
+		if depth == 0 {
+			return fields
+		}
+		for i, child := range v {
+			fields = appendStringLeaves(fields, fmt.Sprintf("%s[%d]", path, i), child, depth-1)
+		}
+	}
+	return fields
+}
+
+// snippet returns the part of a value surrounding a match
+func snippet(value string, idx, length int) string {
+	start := idx - snippetContext
+	prefix := "…"
+	if start <= 0 {
+		start = 0
+		prefix = ""
+	}
+	// Do not cut a multi-byte rune in half
+	for start > 0 && !utf8.RuneStart(value[start]) {
+		start--
+	}
+
+	end := idx + length + snippetContext
+	suffix := "…"
+	if end >= len(value) {
+		end = len(value)
+		suffix = ""
+	}
+	for end < len(value) && !utf8.RuneStart(value[end]) {
+		end++
+	}
+
+	return prefix + value[start:end] + suffix
+}
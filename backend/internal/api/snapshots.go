@@ -0,0 +1,121 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gravitek/crossplane-spy/internal/k8s"
+	"github.com/gravitek/crossplane-spy/internal/models"
+	"github.com/gravitek/crossplane-spy/internal/snapshot"
+)
+
+// liveSnapshotID designates the current state of the cluster in a diff
+const liveSnapshotID = "live"
+
+// listSnapshots returns all snapshots without their objects, oldest first
+func listSnapshots(store snapshot.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		snapshots, err := store.List()
+		if err != nil {
+			log.Printf("Error listing snapshots: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list snapshots"})
+			return
+		}
+
+		c.JSON(http.StatusOK, listResponse("SnapshotList", snapshots, len(snapshots), ""))
+	}
+}
+
+// createSnapshot captures and saves a snapshot of all Crossplane objects
+func createSnapshot(objectCache *k8s.ObjectCache, store snapshot.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !objectCache.HasSynced() {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Object cache is not synced yet, retry later"})
+			return
+		}
+
+		s := snapshot.Capture(objectCache, models.SnapshotTriggerManual, time.Now())
+		if err := store.Save(s); err != nil {
+			log.Printf("Error saving snapshot: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save snapshot"})
+			return
+		}
+
+		s.Objects = nil
+		c.JSON(http.StatusCreated, s)
+	}
+}
+
+// getSnapshot returns a snapshot with its objects
+func getSnapshot(store snapshot.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		s, ok := loadSnapshot(c, store, c.Param("id"))
+		if !ok {
+			return
+		}
+		c.JSON(http.StatusOK, s)
+	}
+}
+
+// deleteSnapshot removes a snapshot
+func deleteSnapshot(store snapshot.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := store.Delete(c.Param("id")); err != nil {
+			log.Printf("Error deleting snapshot %s: %v", c.Param("id"), err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete snapshot"})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// diffSnapshots reports the objects added, removed and changed between two snapshots
+// ?to= defaults to "live", comparing a snapshot with the current state of the cluster
+func diffSnapshots(objectCache *k8s.ObjectCache, store snapshot.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		fromID := c.Query("from")
+		if fromID == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "missing snapshot ID: from"})
+			return
+		}
+		toID := c.DefaultQuery("to", liveSnapshotID)
+
+		from, ok := loadSnapshot(c, store, fromID)
+		if !ok {
+			return
+		}
+
+		var to models.Snapshot
+		if toID == liveSnapshotID {
+			if !objectCache.HasSynced() {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Object cache is not synced yet, retry later"})
+				return
+			}
+			to = snapshot.Capture(objectCache, models.SnapshotTriggerLive, time.Now())
+			to.ID = liveSnapshotID
+		} else if to, ok = loadSnapshot(c, store, toID); !ok {
+			return
+		}
+
+		c.JSON(http.StatusOK, snapshot.Diff(from, to))
+	}
+}
+
+// loadSnapshot gets a snapshot from the store
+// On failure the error response is written and false is returned
+func loadSnapshot(c *gin.Context, store snapshot.Store, id string) (models.Snapshot, bool) {
+	s, found, err := store.Get(id)
+	if err != nil {
+		log.Printf("Error reading snapshot %s: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read snapshot"})
+		return s, false
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("snapshot %q not found", id)})
+		return s, false
+	}
+	return s, true
+}
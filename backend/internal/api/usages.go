@@ -0,0 +1,97 @@
+package api
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gravitek/crossplane-spy/internal/k8s"
+	"github.com/gravitek/crossplane-spy/internal/models"
+	"github.com/gravitek/crossplane-spy/internal/report"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// getUsages returns all Usages and ClusterUsages, with their of and by resources resolved
+func getUsages(client k8s.ResourceReader, objectCache *k8s.ObjectCache) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := context.Background()
+
+		filter, page, err := parseListQuery(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		gvrs, err := client.DiscoverUsageGVRs(ctx)
+		if err != nil {
+			log.Printf("Error discovering usages: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to discover usages"})
+			return
+		}
+
+		var allUsages []models.Usage
+		for _, gvr := range gvrs {
+			namespace, ok := listNamespace(ctx, client, gvr, filter)
+			if !ok {
+				continue
+			}
+			usages, err := client.ListResources(ctx, gvr, namespace, filter.ListOptions())
+			if err != nil {
+				log.Printf("Error listing usages for %v: %v", gvr, err)
+				if status := listErrorStatus(err); status != http.StatusInternalServerError {
+					c.JSON(status, gin.H{"error": "Failed to list usages"})
+					return
+				}
+				continue
+			}
+			allUsages = append(allUsages, filterResources(convertToUsages(usages.Items), filter)...)
+		}
+
+		allUsages, next := paginate(allUsages, page)
+		resolveUsages(ctx, newObjectResolver(client, objectCache), allUsages)
+		c.JSON(http.StatusOK, listResponse("UsageList", allUsages, len(allUsages), next))
+	}
+}
+
+// protectionOf returns the Usages involving an object, with their of and by resources resolved
+func protectionOf(ctx context.Context, client k8s.ResourceReader, objectCache *k8s.ObjectCache, obj *unstructured.Unstructured) models.Protection {
+	ref := models.ObjectRef{APIVersion: obj.GetAPIVersion(), Kind: obj.GetKind(), Namespace: obj.GetNamespace(), Name: obj.GetName()}
+	index := report.NewUsageIndex(objectCache.Objects(k8s.CategoryUsage))
+
+	protection := models.Protection{
+		ProtectedBy: append([]models.Usage{}, index.Protecting(ref)...),
+		Protects:    append([]models.Usage{}, index.UsedBy(ref)...),
+	}
+
+	resolver := newObjectResolver(client, objectCache)
+	resolveUsages(ctx, resolver, protection.ProtectedBy)
+	resolveUsages(ctx, resolver, protection.Protects)
+	return protection
+}
+
+// resolveUsages sets the Found, Ready and Deleting fields of the of and by resources of Usages
+func resolveUsages(ctx context.Context, resolver *objectResolver, usages []models.Usage) {
+	for i := range usages {
+		resolveUsageResource(ctx, resolver, &usages[i].Spec.Of)
+		if usages[i].Spec.By != nil {
+			resolveUsageResource(ctx, resolver, usages[i].Spec.By)
+		}
+	}
+}
+
+// resolveUsageResource looks up a referenced resource, unresolved selectors are left as not found
+func resolveUsageResource(ctx context.Context, resolver *objectResolver, res *models.UsageResource) {
+	if !res.Resolved() {
+		return
+	}
+
+	obj := resolver.find(ctx, res.ObjectRef)
+	if obj == nil {
+		return
+	}
+
+	res.Found = true
+	res.Ready = models.ConvertToResourceStatus(obj).Ready
+	res.Deleting = obj.GetDeletionTimestamp() != nil
+}
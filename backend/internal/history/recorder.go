@@ -0,0 +1,270 @@
+package history
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gravitek/crossplane-spy/internal/models"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Recorder records the condition transitions of the objects it observes
+// and the Kubernetes Events about them
+// It is registered as an event handler on the object cache informers and on the Event informer
+type Recorder struct {
+	store Store
+	now   func() time.Time
+
+	mu sync.Mutex
+	// last holds the last recorded condition of each type per object
+	last map[ObjectKey]map[string]models.Condition
+	// events holds the last recorded count of each Event per object
+	events map[ObjectKey]map[types.UID]int32
+}
+
+var _ cache.ResourceEventHandler = &Recorder{}
+
+// NewRecorder creates a recorder writing to the given store
+func NewRecorder(store Store) *Recorder {
+	return &Recorder{
+		store:  store,
+		now:    time.Now,
+		last:   make(map[ObjectKey]map[string]models.Condition),
+		events: make(map[ObjectKey]map[types.UID]int32),
+	}
+}
+
+// OnAdd records the conditions of a new object, or a new Event
+// Objects from the initial list are recorded too, giving the timeline its starting point
+func (r *Recorder) OnAdd(obj interface{}, isInInitialList bool) {
+	switch o := obj.(type) {
+	case *unstructured.Unstructured:
+		r.observe(o)
+	case *corev1.Event:
+		r.observeEvent(o)
+	}
+}
+
+// OnUpdate records the conditions that changed
+// and the Events whose count increased
+func (r *Recorder) OnUpdate(oldObj, newObj interface{}) {
+	switch o := newObj.(type) {
+	case *unstructured.Unstructured:
+		r.observe(o)
+	case *corev1.Event:
+		r.observeEvent(o)
+	}
+}
+
+// OnDelete forgets the last known state of the object, its history is kept in the store
+func (r *Recorder) OnDelete(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := KeyForObject(u)
+	delete(r.last, key)
+	delete(r.events, key)
+}
+
+// observe compares the conditions of an object with the last recorded ones
+// and appends a transition for every condition whose status, reason or message changed
+// The store is written outside the lock, so that a slow store does not block the handlers of other informers
+func (r *Recorder) observe(obj *unstructured.Unstructured) {
+	key := KeyForObject(obj)
+	conditions := models.ConvertToResourceStatus(obj).Conditions
+
+	if !r.loaded(key) {
+		last := r.loadLast(key)
+		r.mu.Lock()
+		if _, ok := r.last[key]; !ok {
+			r.last[key] = last
+		}
+		r.mu.Unlock()
+	}
+
+	var transitions []models.ConditionTransition
+	observedAt := r.now()
+
+	r.mu.Lock()
+	last, ok := r.last[key]
+	if !ok {
+		// Deleted meanwhile
+		r.mu.Unlock()
+		return
+	}
+	for _, cond := range conditions {
+		previous, known := last[cond.Type]
+		if known && previous.Status == cond.Status && previous.Reason == cond.Reason && previous.Message == cond.Message {
+			continue
+		}
+
+		transition := models.ConditionTransition{
+			Type:               cond.Type,
+			Status:             cond.Status,
+			Reason:             cond.Reason,
+			Message:            cond.Message,
+			LastTransitionTime: cond.LastTransitionTime,
+			ObservedAt:         observedAt,
+		}
+		if known {
+			transition.PreviousStatus = previous.Status
+		}
+		transitions = append(transitions, transition)
+		last[cond.Type] = cond
+	}
+	r.mu.Unlock()
+
+	failed := false
+	for _, transition := range transitions {
+		if err := r.store.Append(key, transition); err != nil {
+			log.Printf("Error recording condition transition of %s %s/%s: %v", key.Kind, key.Namespace, key.Name, err)
+			failed = true
+		}
+	}
+	if failed {
+		// Go back to the recorded state, so that the next update records the transitions again
+		last := r.loadLast(key)
+		r.mu.Lock()
+		if _, ok := r.last[key]; ok {
+			r.last[key] = last
+		}
+		r.mu.Unlock()
+	}
+}
+
+// loaded reports whether the last recorded conditions of an object are known
+func (r *Recorder) loaded(key ObjectKey) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.last[key]
+	return ok
+}
+
+// loadLast rebuilds the last recorded condition of each type from the store,
+// so that a persistent store does not get duplicate transitions after a restart
+func (r *Recorder) loadLast(key ObjectKey) map[string]models.Condition {
+	last := make(map[string]models.Condition)
+
+	transitions, err := r.store.Transitions(key)
+	if err != nil {
+		log.Printf("Error loading condition history of %s %s/%s: %v", key.Kind, key.Namespace, key.Name, err)
+		return last
+	}
+
+	for _, t := range transitions {
+		last[t.Type] = models.Condition{
+			Type:               t.Type,
+			Status:             t.Status,
+			Reason:             t.Reason,
+			Message:            t.Message,
+			LastTransitionTime: t.LastTransitionTime,
+		}
+	}
+	return last
+}
+
+// observeEvent records an Event about an object observed by the recorder, once per increase of its count
+// Events about other objects (Pods, Deployments...) are ignored, the store is written outside the lock
+func (r *Recorder) observeEvent(event *corev1.Event) {
+	involved := event.InvolvedObject
+	gv, err := schema.ParseGroupVersion(involved.APIVersion)
+	if err != nil {
+		return
+	}
+	key := ObjectKey{Group: gv.Group, Kind: involved.Kind, Namespace: involved.Namespace, Name: involved.Name}
+
+	count := event.Count
+	if event.Series != nil && event.Series.Count > count {
+		count = event.Series.Count
+	}
+	if count < 1 {
+		count = 1
+	}
+
+	if !r.loaded(key) {
+		return
+	}
+	r.mu.Lock()
+	_, ok := r.events[key]
+	r.mu.Unlock()
+	if !ok {
+		counts := r.loadEvents(key)
+		r.mu.Lock()
+		if _, ok := r.events[key]; !ok {
+			r.events[key] = counts
+		}
+		r.mu.Unlock()
+	}
+
+	r.mu.Lock()
+	counts, ok := r.events[key]
+	if !ok || count <= counts[event.UID] {
+		r.mu.Unlock()
+		return
+	}
+	counts[event.UID] = count
+	r.mu.Unlock()
+
+	recorded := models.ObjectEvent{
+		UID:            string(event.UID),
+		Type:           event.Type,
+		Reason:         event.Reason,
+		Message:        event.Message,
+		Source:         event.Source.Component,
+		Count:          count,
+		FirstTimestamp: event.FirstTimestamp.Time,
+		LastTimestamp:  event.LastTimestamp.Time,
+		ObservedAt:     r.now(),
+	}
+	if recorded.Source == "" {
+		recorded.Source = event.ReportingController
+	}
+	if recorded.FirstTimestamp.IsZero() {
+		recorded.FirstTimestamp = event.EventTime.Time
+	}
+	if recorded.LastTimestamp.IsZero() {
+		recorded.LastTimestamp = recorded.FirstTimestamp
+		if event.Series != nil {
+			recorded.LastTimestamp = event.Series.LastObservedTime.Time
+		}
+	}
+
+	if err := r.store.AppendEvent(key, recorded); err != nil {
+		log.Printf("Error recording event of %s %s/%s: %v", key.Kind, key.Namespace, key.Name, err)
+		// The recorded counts are loaded again from the store on the next Event
+		r.mu.Lock()
+		delete(r.events, key)
+		r.mu.Unlock()
+	}
+}
+
+// loadEvents rebuilds the last recorded count of each Event from the store,
+// so that a persistent store does not get duplicate Events after a restart
+func (r *Recorder) loadEvents(key ObjectKey) map[types.UID]int32 {
+	counts := make(map[types.UID]int32)
+
+	events, err := r.store.Events(key)
+	if err != nil {
+		log.Printf("Error loading event history of %s %s/%s: %v", key.Kind, key.Namespace, key.Name, err)
+		return counts
+	}
+
+	for _, e := range events {
+		if e.Count > counts[types.UID(e.UID)] {
+			counts[types.UID(e.UID)] = e.Count
+		}
+	}
+	return counts
+}
@@ -0,0 +1,189 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gravitek/crossplane-spy/internal/models"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// withConditions returns a Bucket with the given conditions, as type, status, reason and message
+func withConditions(conditions ...[4]string) *unstructured.Unstructured {
+	var list []interface{}
+	for _, c := range conditions {
+		list = append(list, map[string]interface{}{"type": c[0], "status": c[1], "reason": c[2], "message": c[3]})
+	}
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{"conditions": list},
+	}}
+	obj.SetAPIVersion("s3.aws.upbound.io/v1beta1")
+	obj.SetKind("Bucket")
+	obj.SetName("logs-bucket")
+	return obj
+}
+
+func TestRecorder(t *testing.T) {
+	ready := func(status, reason, message string) [4]string { return [4]string{"Ready", status, reason, message} }
+	synced := [4]string{"Synced", "True", "ReconcileSuccess", ""}
+
+	for _, tc := range []struct {
+		name    string
+		updates []*unstructured.Unstructured
+		// want are the recorded transitions, as type, status and previous status
+		want [][3]string
+	}{
+		{
+			name:    "initial state",
+			updates: []*unstructured.Unstructured{withConditions(ready("False", "Creating", ""), synced)},
+			want:    [][3]string{{"Ready", "False", ""}, {"Synced", "True", ""}},
+		},
+		{
+			name: "unchanged conditions",
+			updates: []*unstructured.Unstructured{
+				withConditions(ready("True", "Available", ""), synced),
+				withConditions(ready("True", "Available", ""), synced),
+			},
+			want: [][3]string{{"Ready", "True", ""}, {"Synced", "True", ""}},
+		},
+		{
+			name: "ready flapping",
+			updates: []*unstructured.Unstructured{
+				withConditions(ready("True", "Available", "")),
+				withConditions(ready("False", "Unavailable", "")),
+				withConditions(ready("True", "Available", "")),
+				withConditions(ready("False", "Unavailable", "")),
+			},
+			want: [][3]string{{"Ready", "True", ""}, {"Ready", "False", "True"}, {"Ready", "True", "False"}, {"Ready", "False", "True"}},
+		},
+		{
+			name: "message only change",
+			updates: []*unstructured.Unstructured{
+				withConditions(ready("False", "ReconcileError", "throttled")),
+				withConditions(ready("False", "ReconcileError", "access denied")),
+			},
+			want: [][3]string{{"Ready", "False", ""}, {"Ready", "False", "False"}},
+		},
+		{
+			name: "condition appearing later",
+			updates: []*unstructured.Unstructured{
+				withConditions(synced),
+				withConditions(synced, ready("True", "Available", "")),
+			},
+			want: [][3]string{{"Synced", "True", ""}, {"Ready", "True", ""}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			store := NewMemoryStore(0, 0)
+			recorder := NewRecorder(store)
+			for i, obj := range tc.updates {
+				if i == 0 {
+					recorder.OnAdd(obj, true)
+				} else {
+					recorder.OnUpdate(tc.updates[i-1], obj)
+				}
+			}
+
+			transitions, err := store.Transitions(KeyForObject(tc.updates[0]))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got [][3]string
+			for _, tr := range transitions {
+				got = append(got, [3]string{tr.Type, tr.Status, tr.PreviousStatus})
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("got transitions %v, want %v", got, tc.want)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Errorf("got transitions %v, want %v", got, tc.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestRecorderDelete(t *testing.T) {
+	store := NewMemoryStore(0, 0)
+	recorder := NewRecorder(store)
+	obj := withConditions([4]string{"Ready", "True", "Available", ""})
+
+	recorder.OnAdd(obj, true)
+	recorder.OnDelete(obj)
+	// The history outlives the object, a recreated object continues it without duplicates
+	recorder.OnAdd(obj, false)
+
+	transitions, err := store.Transitions(KeyForObject(obj))
+	if err != nil || len(transitions) != 1 {
+		t.Errorf("got %d transitions (%v), want 1", len(transitions), err)
+	}
+}
+
+func TestMemoryStoreBounds(t *testing.T) {
+	key := func(name string) ObjectKey { return ObjectKey{Kind: "Bucket", Name: name} }
+	transition := func(i int) models.ConditionTransition {
+		return models.ConditionTransition{Type: "Ready", ObservedAt: time.Unix(int64(i), 0)}
+	}
+
+	for _, tc := range []struct {
+		name  string
+		run   func(s *MemoryStore)
+		check map[string]int
+	}{
+		{
+			name: "oldest transitions of an object dropped",
+			run: func(s *MemoryStore) {
+				for i := 0; i < 5; i++ {
+					_ = s.Append(key("a"), transition(i))
+				}
+			},
+			check: map[string]int{"a": 3},
+		},
+		{
+			name: "least recently updated object evicted",
+			run: func(s *MemoryStore) {
+				_ = s.Append(key("a"), transition(0))
+				_ = s.Append(key("b"), transition(1))
+				_ = s.Append(key("a"), transition(2))
+				_ = s.Append(key("c"), transition(3))
+			},
+			check: map[string]int{"a": 2, "b": 0, "c": 1},
+		},
+		{
+			name: "events bounded separately",
+			run: func(s *MemoryStore) {
+				_ = s.Append(key("a"), transition(0))
+				for i := 0; i < 5; i++ {
+					_ = s.AppendEvent(key("a"), models.ObjectEvent{Count: int32(i + 1)})
+				}
+			},
+			check: map[string]int{"a": 1},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewMemoryStore(3, 2)
+			tc.run(s)
+			for name, want := range tc.check {
+				transitions, _ := s.Transitions(key(name))
+				if len(transitions) != want {
+					t.Errorf("got %d transitions of %s, want %d", len(transitions), name, want)
+				}
+			}
+			if events, _ := s.Events(key("a")); len(events) > 3 {
+				t.Errorf("got %d events, want at most 3", len(events))
+			}
+		})
+	}
+
+	// The most recent transitions are kept, oldest first
+	s := NewMemoryStore(3, 2)
+	for i := 0; i < 5; i++ {
+		_ = s.Append(key("a"), transition(i))
+	}
+	transitions, _ := s.Transitions(key("a"))
+	if len(transitions) != 3 || transitions[0].ObservedAt.Unix() != 2 || transitions[2].ObservedAt.Unix() != 4 {
+		t.Errorf("got transitions %+v, want the last 3", transitions)
+	}
+}
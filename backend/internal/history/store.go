@@ -0,0 +1,157 @@
+package history
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/gravitek/crossplane-spy/internal/models"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Default bounds of the in-memory store, the transitions and Events of an object are bounded separately
+const (
+	DefaultMaxTransitionsPerObject = 100
+	DefaultMaxObjects              = 10000
+)
+
+// ObjectKey identifies an object independently of its API version
+type ObjectKey struct {
+	Group     string
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// KeyForObject returns the key of an unstructured object
+func KeyForObject(obj *unstructured.Unstructured) ObjectKey {
+	gvk := obj.GroupVersionKind()
+	return ObjectKey{
+		Group:     gvk.Group,
+		Kind:      gvk.Kind,
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+	}
+}
+
+// Store keeps the condition transitions observed on objects and the Kubernetes Events about them
+type Store interface {
+	// Append records a transition of an object
+	Append(key ObjectKey, transition models.ConditionTransition) error
+	// Transitions returns the recorded transitions of an object, oldest first
+	Transitions(key ObjectKey) ([]models.ConditionTransition, error)
+	// AppendEvent records an Event about an object
+	AppendEvent(key ObjectKey, event models.ObjectEvent) error
+	// Events returns the recorded Events of an object, oldest first
+	Events(key ObjectKey) ([]models.ObjectEvent, error)
+}
+
+// MemoryStore is a bounded in-memory Store
+// Each object keeps its most recent transitions and Events, and the least recently updated
+// objects are evicted once the object limit is reached
+type MemoryStore struct {
+	maxPerObject int
+	maxObjects   int
+
+	mu      sync.Mutex
+	objects map[ObjectKey]*list.Element
+	lru     *list.List
+}
+
+// memoryEntry is the value of an element of the LRU list
+type memoryEntry struct {
+	key         ObjectKey
+	transitions []models.ConditionTransition
+	events      []models.ObjectEvent
+}
+
+// NewMemoryStore creates an in-memory store with the given bounds
+// Non-positive bounds fall back to the defaults
+func NewMemoryStore(maxPerObject, maxObjects int) *MemoryStore {
+	if maxPerObject <= 0 {
+		maxPerObject = DefaultMaxTransitionsPerObject
+	}
+	if maxObjects <= 0 {
+		maxObjects = DefaultMaxObjects
+	}
+
+	return &MemoryStore{
+		maxPerObject: maxPerObject,
+		maxObjects:   maxObjects,
+		objects:      make(map[ObjectKey]*list.Element),
+		lru:          list.New(),
+	}
+}
+
+// Append records a transition of an object
+func (s *MemoryStore) Append(key ObjectKey, transition models.ConditionTransition) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.touch(key)
+	entry.transitions = append(entry.transitions, transition)
+	if len(entry.transitions) > s.maxPerObject {
+		entry.transitions = entry.transitions[len(entry.transitions)-s.maxPerObject:]
+	}
+	return nil
+}
+
+// AppendEvent records an Event about an object
+func (s *MemoryStore) AppendEvent(key ObjectKey, event models.ObjectEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.touch(key)
+	entry.events = append(entry.events, event)
+	if len(entry.events) > s.maxPerObject {
+		entry.events = entry.events[len(entry.events)-s.maxPerObject:]
+	}
+	return nil
+}
+
+// touch returns the entry of an object, created if needed, as the most recently updated one
+// and evicts the least recently updated objects beyond the object limit
+// The caller must hold the lock
+func (s *MemoryStore) touch(key ObjectKey) *memoryEntry {
+	elem, ok := s.objects[key]
+	if !ok {
+		elem = s.lru.PushFront(&memoryEntry{key: key})
+		s.objects[key] = elem
+	} else {
+		s.lru.MoveToFront(elem)
+	}
+
+	for s.lru.Len() > s.maxObjects {
+		oldest := s.lru.Back()
+		s.lru.Remove(oldest)
+		delete(s.objects, oldest.Value.(*memoryEntry).key)
+	}
+	return elem.Value.(*memoryEntry)
+}
+
+// Transitions returns the recorded transitions of an object, oldest first
+func (s *MemoryStore) Transitions(key ObjectKey) ([]models.ConditionTransition, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elem, ok := s.objects[key]
+	if !ok {
+		return []models.ConditionTransition{}, nil
+	}
+
+	transitions := elem.Value.(*memoryEntry).transitions
+	return append([]models.ConditionTransition(nil), transitions...), nil
+}
+
+// Events returns the recorded Events of an object, oldest first
+func (s *MemoryStore) Events(key ObjectKey) ([]models.ObjectEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elem, ok := s.objects[key]
+	if !ok {
+		return []models.ObjectEvent{}, nil
+	}
+
+	events := elem.Value.(*memoryEntry).events
+	return append([]models.ObjectEvent{}, events...), nil
+}
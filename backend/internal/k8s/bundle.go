@@ -0,0 +1,191 @@
+package k8s
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// manifestExtensions are the file extensions read from a bundle
+var manifestExtensions = []string{".yaml", ".yml", ".json"}
+
+// loadManifests reads all objects from a directory, a tarball (.tar, .tar.gz, .tgz) or a single manifest file
+// Files that cannot be parsed are skipped with a warning, support bundles often contain other data
+func loadManifests(path string) ([]*unstructured.Unstructured, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle: %w", err)
+	}
+
+	if info.IsDir() {
+		return loadManifestDir(path)
+	}
+
+	switch {
+	case strings.HasSuffix(path, ".tar.gz"), strings.HasSuffix(path, ".tgz"), strings.HasSuffix(path, ".tar"):
+		return loadManifestTarball(path)
+	default:
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle: %w", err)
+		}
+		defer f.Close()
+		return decodeManifests(f)
+	}
+}
+
+// loadManifestDir reads all manifest files of a directory tree
+func loadManifestDir(dir string) ([]*unstructured.Unstructured, error) {
+	var objs []*unstructured.Unstructured
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !isManifestFile(path) {
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		fileObjs, err := decodeManifests(f)
+		if err != nil {
+			log.Printf("Skipping %s: %v", path, err)
+			return nil
+		}
+		objs = append(objs, fileObjs...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle directory %s: %w", dir, err)
+	}
+
+	return objs, nil
+}
+
+// loadManifestTarball reads all manifest files of a tarball, optionally gzipped
+func loadManifestTarball(path string) ([]*unstructured.Unstructured, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle: %w", err)
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if !strings.HasSuffix(path, ".tar") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress bundle %s: %w", path, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	var objs []*unstructured.Unstructured
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle %s: %w", path, err)
+		}
+		if header.Typeflag != tar.TypeReg || !isManifestFile(header.Name) {
+			continue
+		}
+
+		fileObjs, err := decodeManifests(tr)
+		if err != nil {
+			log.Printf("Skipping %s: %v", header.Name, err)
+			continue
+		}
+		objs = append(objs, fileObjs...)
+	}
+
+	return objs, nil
+}
+
+// isManifestFile reports whether a file may contain manifests
+func isManifestFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, e := range manifestExtensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeManifests reads the objects of a YAML stream (possibly multi-document) or a JSON document
+// Lists (e.g. the output of kubectl get -o yaml) and JSON arrays are flattened
+func decodeManifests(r io.Reader) ([]*unstructured.Unstructured, error) {
+	var objs []*unstructured.Unstructured
+
+	decoder := utilyaml.NewYAMLOrJSONDecoder(r, 4096)
+	for {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			if err == io.EOF {
+				return objs, nil
+			}
+			return nil, err
+		}
+		objs = collectObjects(raw, objs)
+	}
+}
+
+// collectObjects appends the objects of a decoded document
+// Documents that are not Kubernetes objects are ignored
+func collectObjects(raw []byte, objs []*unstructured.Unstructured) []*unstructured.Unstructured {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return objs
+	}
+
+	if raw[0] == '[' {
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return objs
+		}
+		for _, item := range items {
+			objs = collectObjects(item, objs)
+		}
+		return objs
+	}
+
+	var list struct {
+		Kind  string            `json:"kind"`
+		Items []json.RawMessage `json:"items"`
+	}
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return objs
+	}
+	if list.Items != nil && (list.Kind == "" || strings.HasSuffix(list.Kind, "List")) {
+		for _, item := range list.Items {
+			objs = collectObjects(item, objs)
+		}
+		return objs
+	}
+
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(raw); err != nil || obj.GetName() == "" {
+		return objs
+	}
+	return append(objs, obj)
+}
@@ -0,0 +1,242 @@
+package k8s
+
+import (
+	"context"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Categories of the Crossplane objects kept in the object cache
+const (
+	CategoryProvider       = "provider"
+	CategoryProviderConfig = "providerconfig"
+	CategoryXRD            = "xrd"
+	CategoryComposition    = "composition"
+	CategoryFunction       = "function"
+	CategoryComposite      = "composite"
+	CategoryClaim          = "claim"
+	CategoryManaged        = "managed"
+	CategoryUsage          = "usage"
+)
+
+// cacheRefreshInterval is how often the cached kinds are re-discovered,
+// in addition to the refresh triggered by discovery invalidations
+const cacheRefreshInterval = 5 * time.Minute
+
+// CachedKind is a resource type watched by the object cache
+type CachedKind struct {
+	GVR      schema.GroupVersionResource
+	Category string
+}
+
+// CachedObject is an object held by the object cache with the kind it was listed as
+type CachedObject struct {
+	CachedKind
+	Object *unstructured.Unstructured
+}
+
+// ObjectCache keeps an informer-backed, in-memory copy of every Crossplane object
+// The set of watched kinds follows the XRDs, ProviderConfigs and managed resources
+// discovered in the cluster
+type ObjectCache struct {
+	client *Client
+
+	mu        sync.RWMutex
+	informers map[schema.GroupVersionResource]*kindInformer
+	handlers  []cache.ResourceEventHandler
+}
+
+// kindInformer is the informer of a single cached kind
+type kindInformer struct {
+	kind     CachedKind
+	informer cache.SharedIndexInformer
+	stop     chan struct{}
+}
+
+// NewObjectCache creates an object cache, call Start to begin watching
+func NewObjectCache(client *Client) *ObjectCache {
+	return &ObjectCache{
+		client:    client,
+		informers: make(map[schema.GroupVersionResource]*kindInformer),
+	}
+}
+
+// Start discovers the Crossplane kinds and watches them until the context is cancelled
+// Kinds are re-discovered whenever the discovery cache is invalidated
+func (oc *ObjectCache) Start(ctx context.Context) {
+	oc.refresh(ctx)
+
+	go func() {
+		ticker := time.NewTicker(cacheRefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				oc.stopAll()
+				return
+			case <-oc.client.discoveryChanges:
+				oc.refresh(ctx)
+			case <-ticker.C:
+				oc.refresh(ctx)
+			}
+		}
+	}()
+}
+
+// AddEventHandler registers a handler on every current and future informer of the cache
+func (oc *ObjectCache) AddEventHandler(handler cache.ResourceEventHandler) {
+	oc.mu.Lock()
+	defer oc.mu.Unlock()
+
+	oc.handlers = append(oc.handlers, handler)
+	for gvr, ki := range oc.informers {
+		if _, err := ki.informer.AddEventHandler(handler); err != nil {
+			log.Printf("Error adding event handler for %v: %v", gvr, err)
+		}
+	}
+}
+
+// Kinds returns the kinds currently watched by the cache
+func (oc *ObjectCache) Kinds() []CachedKind {
+	oc.mu.RLock()
+	defer oc.mu.RUnlock()
+
+	kinds := make([]CachedKind, 0, len(oc.informers))
+	for _, ki := range oc.informers {
+		kinds = append(kinds, ki.kind)
+	}
+	return kinds
+}
+
+// Objects returns every cached object, optionally restricted to some categories
+// The returned objects are shared with the cache and must not be modified
+func (oc *ObjectCache) Objects(categories ...string) []CachedObject {
+	oc.mu.RLock()
+	defer oc.mu.RUnlock()
+
+	var objects []CachedObject
+	for _, ki := range oc.informers {
+		if len(categories) > 0 && !slices.Contains(categories, ki.kind.Category) {
+			continue
+		}
+		for _, item := range ki.informer.GetStore().List() {
+			if obj, ok := item.(*unstructured.Unstructured); ok {
+				objects = append(objects, CachedObject{CachedKind: ki.kind, Object: obj})
+			}
+		}
+	}
+	return objects
+}
+
+// HasSynced reports whether every watched kind completed its initial list
+func (oc *ObjectCache) HasSynced() bool {
+	oc.mu.RLock()
+	defer oc.mu.RUnlock()
+
+	for _, ki := range oc.informers {
+		if !ki.informer.HasSynced() {
+			return false
+		}
+	}
+	return true
+}
+
+// refresh starts informers for newly discovered kinds and stops those that disappeared
+func (oc *ObjectCache) refresh(ctx context.Context) {
+	kinds, failed := oc.discoverKinds(ctx)
+
+	oc.mu.Lock()
+	defer oc.mu.Unlock()
+
+	wanted := make(map[schema.GroupVersionResource]bool, len(kinds))
+	for _, kind := range kinds {
+		wanted[kind.GVR] = true
+		if _, ok := oc.informers[kind.GVR]; ok {
+			continue
+		}
+
+		informer := dynamicinformer.NewFilteredDynamicInformer(
+			oc.client.DynamicClient, kind.GVR, metav1.NamespaceAll, 0, cache.Indexers{}, nil,
+		).Informer()
+		for _, handler := range oc.handlers {
+			if _, err := informer.AddEventHandler(handler); err != nil {
+				log.Printf("Error adding event handler for %v: %v", kind.GVR, err)
+			}
+		}
+
+		ki := &kindInformer{kind: kind, informer: informer, stop: make(chan struct{})}
+		oc.informers[kind.GVR] = ki
+		go informer.Run(ki.stop)
+	}
+
+	for gvr, ki := range oc.informers {
+		// Keep watching kinds whose discovery failed, the failure may be transient
+		if !wanted[gvr] && !failed[ki.kind.Category] {
+			close(ki.stop)
+			delete(oc.informers, gvr)
+		}
+	}
+}
+
+// stopAll stops every informer of the cache
+func (oc *ObjectCache) stopAll() {
+	oc.mu.Lock()
+	defer oc.mu.Unlock()
+
+	for gvr, ki := range oc.informers {
+		close(ki.stop)
+		delete(oc.informers, gvr)
+	}
+}
+
+// discoverKinds returns every Crossplane kind to watch, and the categories whose discovery failed
+func (oc *ObjectCache) discoverKinds(ctx context.Context) ([]CachedKind, map[string]bool) {
+	kinds := []CachedKind{
+		{GVR: ProviderGVR, Category: CategoryProvider},
+		{GVR: XRDGVR, Category: CategoryXRD},
+		{GVR: CompositionGVR, Category: CategoryComposition},
+		{GVR: FunctionGVR, Category: CategoryFunction},
+	}
+	seen := make(map[schema.GroupVersionResource]bool)
+	for _, kind := range kinds {
+		seen[kind.GVR] = true
+	}
+
+	discovered := []struct {
+		category string
+		discover func(context.Context) ([]schema.GroupVersionResource, error)
+	}{
+		{CategoryProviderConfig, oc.client.DiscoverProviderConfigGVRs},
+		{CategoryComposite, oc.client.DiscoverXRDGVRs},
+		{CategoryClaim, oc.client.DiscoverClaimGVRs},
+		{CategoryManaged, oc.client.DiscoverManagedResourceGVRs},
+		{CategoryUsage, oc.client.DiscoverUsageGVRs},
+	}
+	failed := make(map[string]bool)
+	for _, d := range discovered {
+		gvrs, err := d.discover(ctx)
+		if err != nil {
+			log.Printf("Error discovering %s kinds for the object cache: %v", d.category, err)
+			failed[d.category] = true
+			continue
+		}
+		for _, gvr := range gvrs {
+			if seen[gvr] {
+				continue
+			}
+			seen[gvr] = true
+			kinds = append(kinds, CachedKind{GVR: gvr, Category: d.category})
+		}
+	}
+
+	return kinds, failed
+}
@@ -5,9 +5,12 @@ import (
 	"os"
 	"path/filepath"
 
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
@@ -17,8 +20,17 @@ type Client struct {
 	Clientset kubernetes.Interface
 	// DynamicClient is used for working with custom resources
 	DynamicClient dynamic.Interface
-	// Config is the Kubernetes REST config
+	// Config is the Kubernetes REST config, nil in offline mode
 	Config *rest.Config
+	// Discovery is an in-memory cached discovery client, invalidated when CRDs or XRDs change
+	Discovery discovery.CachedDiscoveryInterface
+	// Mapper resolves resources to kinds and scopes using the cached discovery data
+	Mapper *restmapper.DeferredDiscoveryRESTMapper
+	// Offline is set when objects are loaded from manifests (see NewOfflineClient) instead of a cluster
+	Offline bool
+
+	// discoveryChanges is notified when the discovery cache is invalidated
+	discoveryChanges chan struct{}
 }
 
 // NewClient creates a new Kubernetes client
@@ -45,11 +57,23 @@ func NewClient() (*Client, error) {
 		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
 	}
 
+	return newClient(clientset, dynamicClient, clientset.Discovery(), config), nil
+}
+
+// newClient creates a client from its underlying clients
+func newClient(clientset kubernetes.Interface, dynamicClient dynamic.Interface, discoveryClient discovery.DiscoveryInterface, config *rest.Config) *Client {
+	// Cache discovery results in memory, they are invalidated by the discovery watch
+	cachedDiscovery := memory.NewMemCacheClient(discoveryClient)
+
 	return &Client{
 		Clientset:     clientset,
 		DynamicClient: dynamicClient,
 		Config:        config,
-	}, nil
+		Discovery:     cachedDiscovery,
+		Mapper:        restmapper.NewDeferredDiscoveryRESTMapper(cachedDiscovery),
+
+		discoveryChanges: make(chan struct{}, 1),
+	}
 }
 
 // getKubeconfigConfig attempts to load kubeconfig from standard locations
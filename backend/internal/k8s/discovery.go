@@ -3,15 +3,29 @@ package k8s
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 // DiscoverXRDGVRs discovers all composite resource GVRs from XRDs
 // This is used to list all composite resource instances in the cluster
 func (c *Client) DiscoverXRDGVRs(ctx context.Context) ([]schema.GroupVersionResource, error) {
-	xrds, err := c.ListXRDs(ctx)
+	return c.discoverXRDDefinedGVRs(ctx, "names")
+}
+
+// DiscoverClaimGVRs discovers all claim GVRs from XRDs that offer a claim
+// XRDs without spec.claimNames (e.g. Crossplane v2 namespaced XRs) are skipped
+func (c *Client) DiscoverClaimGVRs(ctx context.Context) ([]schema.GroupVersionResource, error) {
+	return c.discoverXRDDefinedGVRs(ctx, "claimNames")
+}
+
+// discoverXRDDefinedGVRs returns the GVRs defined by XRDs, using the plural found in spec.<namesField>
+func (c *Client) discoverXRDDefinedGVRs(ctx context.Context, namesField string) ([]schema.GroupVersionResource, error) {
+	xrds, err := c.ListXRDs(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list XRDs: %w", err)
 	}
@@ -24,8 +38,8 @@ func (c *Client) DiscoverXRDGVRs(ctx context.Context) ([]schema.GroupVersionReso
 			continue
 		}
 
-		// Get the plural name from spec.names.plural
-		plural, found, err := getNestedString(xrd.Object, "spec", "names", "plural")
+		// Get the plural name from spec.names.plural or spec.claimNames.plural
+		plural, found, err := getNestedString(xrd.Object, "spec", namesField, "plural")
 		if err != nil || !found {
 			continue
 		}
@@ -69,11 +83,69 @@ func (c *Client) DiscoverXRDGVRs(ctx context.Context) ([]schema.GroupVersionReso
 	return gvrs, nil
 }
 
+// DiscoverManagedResourceGVRs discovers all managed resource (MR) GVRs
+// Crossplane providers install their MR CRDs in the "managed" category
+func (c *Client) DiscoverManagedResourceGVRs(ctx context.Context) ([]schema.GroupVersionResource, error) {
+	resourceLists, err := c.Discovery.ServerPreferredResources()
+	if err != nil && len(resourceLists) == 0 {
+		return nil, fmt.Errorf("failed to discover API resources: %w", err)
+	}
+	// Partial discovery failures (e.g. an unavailable aggregated API) are ignored
+
+	var gvrs []schema.GroupVersionResource
+	for _, list := range resourceLists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+
+		for _, resource := range list.APIResources {
+			// Skip subresources such as buckets/status
+			if strings.Contains(resource.Name, "/") {
+				continue
+			}
+			if slices.Contains(resource.Categories, "managed") {
+				gvrs = append(gvrs, gv.WithResource(resource.Name))
+			}
+		}
+	}
+
+	return gvrs, nil
+}
+
+// usageGroups are the API groups serving Usages:
+// apiextensions.crossplane.io before Crossplane v2, protection.crossplane.io (Usage and ClusterUsage) since v2
+var usageGroups = []string{CompositionGVR.Group, "protection.crossplane.io"}
+
+// DiscoverUsageGVRs discovers the Usage and ClusterUsage GVRs served by the cluster, in their preferred version
+func (c *Client) DiscoverUsageGVRs(ctx context.Context) ([]schema.GroupVersionResource, error) {
+	resourceLists, err := c.Discovery.ServerPreferredResources()
+	if err != nil && len(resourceLists) == 0 {
+		return nil, fmt.Errorf("failed to discover API resources: %w", err)
+	}
+
+	var gvrs []schema.GroupVersionResource
+	for _, list := range resourceLists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil || !slices.Contains(usageGroups, gv.Group) {
+			continue
+		}
+
+		for _, resource := range list.APIResources {
+			if resource.Kind == "Usage" || resource.Kind == "ClusterUsage" {
+				gvrs = append(gvrs, gv.WithResource(resource.Name))
+			}
+		}
+	}
+
+	return gvrs, nil
+}
+
 // DiscoverProviderConfigGVRs discovers all ProviderConfig GVRs
 // ProviderConfigs have different groups depending on the provider (e.g., aws.upbound.io, gcp.upbound.io)
 func (c *Client) DiscoverProviderConfigGVRs(ctx context.Context) ([]schema.GroupVersionResource, error) {
-	// List all API resources
-	apiGroups, err := c.Clientset.Discovery().ServerGroups()
+	// List all API groups (served from the discovery cache after the first call)
+	apiGroups, err := c.Discovery.ServerGroups()
 	if err != nil {
 		return nil, fmt.Errorf("failed to discover API groups: %w", err)
 	}
@@ -91,7 +163,7 @@ func (c *Client) DiscoverProviderConfigGVRs(ctx context.Context) ([]schema.Group
 		version := group.PreferredVersion.Version
 
 		// List resources in this group/version
-		resourceList, err := c.Clientset.Discovery().ServerResourcesForGroupVersion(
+		resourceList, err := c.Discovery.ServerResourcesForGroupVersion(
 			fmt.Sprintf("%s/%s", groupName, version),
 		)
 		if err != nil {
@@ -135,21 +207,56 @@ func isProviderGroup(group string) bool {
 }
 
 // IsClusterScoped checks if a resource is cluster-scoped or namespace-scoped
+// The scope is resolved through the cached REST mapper, so repeated calls do not hit discovery
 func (c *Client) IsClusterScoped(ctx context.Context, gvr schema.GroupVersionResource) (bool, error) {
-	resourceList, err := c.Clientset.Discovery().ServerResourcesForGroupVersion(
-		fmt.Sprintf("%s/%s", gvr.Group, gvr.Version),
-	)
+	gvk, err := c.Mapper.KindFor(gvr)
 	if err != nil {
-		return false, fmt.Errorf("failed to discover resources: %w", err)
+		return false, fmt.Errorf("resource not found: %s: %w", gvr.Resource, err)
 	}
 
-	for _, resource := range resourceList.APIResources {
-		if resource.Name == gvr.Resource {
-			return !resource.Namespaced, nil
-		}
+	mapping, err := c.Mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return false, fmt.Errorf("failed to map resource %s: %w", gvr.Resource, err)
 	}
 
-	return false, fmt.Errorf("resource not found: %s", gvr.Resource)
+	return mapping.Scope.Name() == meta.RESTScopeNameRoot, nil
+}
+
+// ResolveResource resolves a user-provided kind to a GVR and its scope
+// The kind can be a Kind, a plural or singular resource name, optionally qualified
+// with its group (e.g. "Bucket", "buckets" or "buckets.s3.aws.upbound.io")
+func (c *Client) ResolveResource(ctx context.Context, kind string) (schema.GroupVersionResource, bool, error) {
+	fullySpecified, groupResource := schema.ParseResourceArg(strings.ToLower(kind))
+
+	var gvr schema.GroupVersionResource
+	var err error
+	if fullySpecified != nil {
+		gvr, err = c.Mapper.ResourceFor(*fullySpecified)
+	}
+	if fullySpecified == nil || err != nil {
+		gvr, err = c.Mapper.ResourceFor(groupResource.WithVersion(""))
+	}
+	if err != nil {
+		return schema.GroupVersionResource{}, false, err
+	}
+
+	clusterScoped, err := c.IsClusterScoped(ctx, gvr)
+	if err != nil {
+		return schema.GroupVersionResource{}, false, err
+	}
+	return gvr, clusterScoped, nil
+}
+
+// InvalidateDiscovery drops all cached discovery data
+// The next discovery call or REST mapping lookup will query the API server again
+func (c *Client) InvalidateDiscovery() {
+	c.Mapper.Reset()
+
+	// Notify the object cache without blocking, a pending notification is enough
+	select {
+	case c.discoveryChanges <- struct{}{}:
+	default:
+	}
 }
 
 // Helper functions to extract nested fields from unstructured objects
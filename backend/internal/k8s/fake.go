@@ -0,0 +1,68 @@
+package k8s
+
+import (
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+// NewFakeClient creates a client backed by the client-go fake dynamic, typed and discovery clients,
+// serving the given objects
+// Types are discovered from the CRDs and XRDs among the objects, and from the objects themselves for other types
+func NewFakeClient(objs ...*unstructured.Unstructured) (*Client, error) {
+	types := newOfflineTypes(objs)
+
+	// Register the list kind of every served resource, the fake client cannot list unknown resources
+	listKinds := make(map[schema.GroupVersionResource]string)
+	for _, t := range types.ordered {
+		for _, v := range t.versions {
+			listKinds[schema.GroupVersionResource{Group: t.group, Version: v, Resource: t.resource.Name}] = t.resource.Kind + "List"
+		}
+	}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds)
+
+	// Objects are served in every version of their type, as an API server would (without schema conversion)
+	var typed []runtime.Object
+	for _, obj := range objs {
+		gvk := obj.GroupVersionKind()
+		t := types.byKind[gvk.GroupKind()]
+
+		for _, v := range t.versions {
+			served := obj.DeepCopy()
+			served.SetAPIVersion(schema.GroupVersion{Group: t.group, Version: v}.String())
+			if err := trackObject(dynamicClient.Tracker(), schema.GroupVersionResource{Group: t.group, Version: v, Resource: t.resource.Name}, served); err != nil {
+				return nil, fmt.Errorf("failed to load %s %s/%s: %w", gvk.Kind, obj.GetNamespace(), obj.GetName(), err)
+			}
+		}
+
+		// Built-in Kubernetes objects (e.g. pods) are also served by the standard client
+		if typedObj, err := scheme.Scheme.New(gvk); err == nil {
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, typedObj); err == nil {
+				typed = append(typed, typedObj)
+			}
+		}
+	}
+
+	discoveryClient := &fakediscovery.FakeDiscovery{
+		Fake: &clienttesting.Fake{Resources: types.resourceLists()},
+	}
+
+	return newClient(kubefake.NewClientset(typed...), offlineDynamicClient{dynamicClient, listKinds}, discoveryClient, nil), nil
+}
+
+// trackObject adds an object to the fake object tracker, replacing any duplicate
+func trackObject(tracker clienttesting.ObjectTracker, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error {
+	err := tracker.Create(gvr, obj, obj.GetNamespace())
+	if apierrors.IsAlreadyExists(err) {
+		return tracker.Update(gvr, obj, obj.GetNamespace())
+	}
+	return err
+}
@@ -0,0 +1,338 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/dynamic"
+)
+
+// offlineType is a resource type served by an offline client
+type offlineType struct {
+	group    string
+	versions []string
+	resource metav1.APIResource
+}
+
+// builtinOfflineTypes are the Crossplane types always served offline,
+// so that their listings work even when the bundle does not contain their CRDs
+var builtinOfflineTypes = []offlineType{
+	{group: ProviderGVR.Group, versions: []string{"v1"}, resource: metav1.APIResource{Name: "providers", SingularName: "provider", Kind: "Provider", Categories: []string{"crossplane", "pkg"}}},
+	{group: ProviderRevisionGVR.Group, versions: []string{"v1"}, resource: metav1.APIResource{Name: "providerrevisions", SingularName: "providerrevision", Kind: "ProviderRevision", Categories: []string{"crossplane", "pkgrev"}}},
+	{group: DeploymentRuntimeConfigGVR.Group, versions: []string{"v1beta1"}, resource: metav1.APIResource{Name: "deploymentruntimeconfigs", SingularName: "deploymentruntimeconfig", Kind: "DeploymentRuntimeConfig", Categories: []string{"crossplane"}}},
+	{group: FunctionGVR.Group, versions: []string{"v1", "v1beta1"}, resource: metav1.APIResource{Name: "functions", SingularName: "function", Kind: "Function", Categories: []string{"crossplane", "pkg"}}},
+	{group: XRDGVR.Group, versions: []string{"v1", "v2"}, resource: metav1.APIResource{Name: "compositeresourcedefinitions", SingularName: "compositeresourcedefinition", Kind: "CompositeResourceDefinition", ShortNames: []string{"xrd", "xrds"}, Categories: []string{"crossplane"}}},
+	{group: CompositionGVR.Group, versions: []string{"v1"}, resource: metav1.APIResource{Name: "compositions", SingularName: "composition", Kind: "Composition", ShortNames: []string{"comp"}, Categories: []string{"crossplane"}}},
+	{group: CompositionRevisionGVR.Group, versions: []string{"v1"}, resource: metav1.APIResource{Name: "compositionrevisions", SingularName: "compositionrevision", Kind: "CompositionRevision", ShortNames: []string{"comprev"}, Categories: []string{"crossplane"}}},
+	{group: CompositionGVR.Group, versions: []string{"v1beta1", "v1alpha1"}, resource: metav1.APIResource{Name: "environmentconfigs", SingularName: "environmentconfig", Kind: "EnvironmentConfig", ShortNames: []string{"envcfg"}, Categories: []string{"crossplane"}}},
+	{group: CompositionGVR.Group, versions: []string{"v1beta1", "v1alpha1"}, resource: metav1.APIResource{Name: "usages", SingularName: "usage", Kind: "Usage", Categories: []string{"crossplane"}}},
+	{group: "protection.crossplane.io", versions: []string{"v1beta1"}, resource: metav1.APIResource{Name: "usages", SingularName: "usage", Kind: "Usage", Namespaced: true, Categories: []string{"crossplane"}}},
+	{group: "protection.crossplane.io", versions: []string{"v1beta1"}, resource: metav1.APIResource{Name: "clusterusages", SingularName: "clusterusage", Kind: "ClusterUsage", Categories: []string{"crossplane"}}},
+	{group: CRDGVR.Group, versions: []string{"v1"}, resource: metav1.APIResource{Name: "customresourcedefinitions", SingularName: "customresourcedefinition", Kind: "CustomResourceDefinition", ShortNames: []string{"crd", "crds"}}},
+}
+
+// NewOfflineClient creates a client serving the objects of a directory or tarball of YAML/JSON manifests,
+// such as a support bundle, instead of a live cluster
+// Discovery is rebuilt from the CRDs and XRDs of the bundle, and from the objects themselves for other types
+func NewOfflineClient(path string) (*Client, error) {
+	objs, err := loadManifests(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(objs) == 0 {
+		return nil, fmt.Errorf("no Kubernetes objects found in %s", path)
+	}
+
+	client, err := NewFakeClient(objs...)
+	if err != nil {
+		return nil, err
+	}
+	client.Offline = true
+
+	log.Printf("Loaded %d objects from %s", len(objs), path)
+	return client, nil
+}
+
+// offlineTypes indexes the resource types served by an offline client
+type offlineTypes struct {
+	byKind  map[schema.GroupKind]*offlineType
+	ordered []*offlineType
+}
+
+// newOfflineTypes collects the types of the bundle objects
+// CRDs take precedence over the types derived from XRDs, which take precedence over guesses from objects
+func newOfflineTypes(objs []*unstructured.Unstructured) *offlineTypes {
+	types := &offlineTypes{byKind: make(map[schema.GroupKind]*offlineType)}
+
+	for _, obj := range objs {
+		if obj.GroupVersionKind().GroupKind() == (schema.GroupKind{Group: CRDGVR.Group, Kind: "CustomResourceDefinition"}) {
+			types.addCRD(obj)
+		}
+	}
+	for _, obj := range objs {
+		if obj.GroupVersionKind().GroupKind() == (schema.GroupKind{Group: XRDGVR.Group, Kind: "CompositeResourceDefinition"}) {
+			types.addXRD(obj)
+		}
+	}
+	for _, builtin := range builtinOfflineTypes {
+		builtin.versions = append([]string(nil), builtin.versions...)
+		types.add(&builtin)
+	}
+	for _, obj := range objs {
+		types.addObject(obj)
+	}
+
+	return types
+}
+
+// add registers a type unless its kind is already known
+func (t *offlineTypes) add(typ *offlineType) {
+	gk := schema.GroupKind{Group: typ.group, Kind: typ.resource.Kind}
+	if _, ok := t.byKind[gk]; ok || typ.resource.Name == "" || len(typ.versions) == 0 {
+		return
+	}
+	if len(typ.resource.Verbs) == 0 {
+		typ.resource.Verbs = metav1.Verbs{"get", "list", "watch"}
+	}
+	t.byKind[gk] = typ
+	t.ordered = append(t.ordered, typ)
+}
+
+// addCRD registers the type defined by a CRD, with its served versions
+func (t *offlineTypes) addCRD(crd *unstructured.Unstructured) {
+	group, _, _ := getNestedString(crd.Object, "spec", "group")
+	scope, _, _ := getNestedString(crd.Object, "spec", "scope")
+
+	t.add(&offlineType{
+		group:    group,
+		versions: servedVersions(crd),
+		resource: namesResource(crd.Object, "names", scope == "Namespaced"),
+	})
+}
+
+// addXRD registers the composite resource and claim types defined by an XRD
+// Composite resources are cluster-scoped in Crossplane v1 and namespaced by default in v2
+func (t *offlineTypes) addXRD(xrd *unstructured.Unstructured) {
+	group, _, _ := getNestedString(xrd.Object, "spec", "group")
+	versions := servedVersions(xrd)
+
+	namespaced := false
+	if xrd.GroupVersionKind().Version != "v1" {
+		scope, found, _ := getNestedString(xrd.Object, "spec", "scope")
+		namespaced = !found || scope == "Namespaced"
+	}
+
+	composite := namesResource(xrd.Object, "names", namespaced)
+	composite.Categories = append(composite.Categories, CategoryComposite)
+	t.add(&offlineType{group: group, versions: versions, resource: composite})
+
+	if _, found, _ := getNestedString(xrd.Object, "spec", "claimNames", "kind"); found {
+		claim := namesResource(xrd.Object, "claimNames", true)
+		claim.Categories = append(claim.Categories, CategoryClaim)
+		t.add(&offlineType{group: group, versions: versions, resource: claim})
+	}
+}
+
+// addObject registers the type of an object whose type is not defined by the bundle, guessing its resource name
+// Objects with spec.forProvider are assumed to be managed resources
+// A version of a known type that the bundle does not declare is served too
+func (t *offlineTypes) addObject(obj *unstructured.Unstructured) {
+	gvk := obj.GroupVersionKind()
+
+	if typ, ok := t.byKind[gvk.GroupKind()]; ok {
+		if !containsString(typ.versions, gvk.Version) {
+			typ.versions = append(typ.versions, gvk.Version)
+		}
+		return
+	}
+
+	plural, singular := meta.UnsafeGuessKindToResource(gvk)
+	resource := metav1.APIResource{
+		Name:         plural.Resource,
+		SingularName: singular.Resource,
+		Namespaced:   obj.GetNamespace() != "",
+		Kind:         gvk.Kind,
+	}
+	if _, found, _ := unstructured.NestedMap(obj.Object, "spec", "forProvider"); found {
+		resource.Categories = []string{CategoryManaged}
+	}
+
+	t.add(&offlineType{group: gvk.Group, versions: []string{gvk.Version}, resource: resource})
+}
+
+// resourceLists returns the discovery data of the types
+// The versions of each group are sorted by priority, the first one being the preferred version
+func (t *offlineTypes) resourceLists() []*metav1.APIResourceList {
+	byGroupVersion := make(map[schema.GroupVersion]*metav1.APIResourceList)
+	for _, typ := range t.ordered {
+		for _, v := range typ.versions {
+			gv := schema.GroupVersion{Group: typ.group, Version: v}
+			list, ok := byGroupVersion[gv]
+			if !ok {
+				list = &metav1.APIResourceList{GroupVersion: gv.String()}
+				byGroupVersion[gv] = list
+			}
+			list.APIResources = append(list.APIResources, typ.resource)
+		}
+	}
+
+	lists := make([]*metav1.APIResourceList, 0, len(byGroupVersion))
+	for _, list := range byGroupVersion {
+		lists = append(lists, list)
+	}
+	sort.Slice(lists, func(i, j int) bool {
+		gi, _ := schema.ParseGroupVersion(lists[i].GroupVersion)
+		gj, _ := schema.ParseGroupVersion(lists[j].GroupVersion)
+		if gi.Group != gj.Group {
+			return gi.Group < gj.Group
+		}
+		return version.CompareKubeAwareVersionStrings(gi.Version, gj.Version) > 0
+	})
+
+	return lists
+}
+
+// servedVersions returns the served versions of a CRD or XRD
+func servedVersions(obj *unstructured.Unstructured) []string {
+	versions, _, _ := getNestedSlice(obj.Object, "spec", "versions")
+
+	var served []string
+	for _, v := range versions {
+		vMap, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, found, _ := getNestedString(vMap, "name")
+		if !found {
+			continue
+		}
+		if ok, _, _ := getNestedBool(vMap, "served"); ok {
+			served = append(served, name)
+		}
+	}
+	return served
+}
+
+// namesResource builds the discovery entry of a type from the spec.<namesField> of a CRD or XRD
+func namesResource(obj map[string]interface{}, namesField string, namespaced bool) metav1.APIResource {
+	resource := metav1.APIResource{Namespaced: namespaced}
+	resource.Name, _, _ = getNestedString(obj, "spec", namesField, "plural")
+	resource.SingularName, _, _ = getNestedString(obj, "spec", namesField, "singular")
+	resource.Kind, _, _ = getNestedString(obj, "spec", namesField, "kind")
+	resource.ShortNames, _, _ = unstructured.NestedStringSlice(obj, "spec", namesField, "shortNames")
+	resource.Categories, _, _ = unstructured.NestedStringSlice(obj, "spec", namesField, "categories")
+	return resource
+}
+
+// containsString reports whether a slice contains a string
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// offlineDynamicClient serves lists the way the API server does: ordered by namespace and name,
+// with field selectors on metadata.name and metadata.namespace and with limit/continue paging,
+// none of which the fake dynamic client supports
+// Listing a resource that is not served returns NotFound, where the fake client would panic
+type offlineDynamicClient struct {
+	dynamic.Interface
+	served map[schema.GroupVersionResource]string
+}
+
+func (c offlineDynamicClient) Resource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	_, served := c.served[gvr]
+	return offlineResource{c.Interface.Resource(gvr), gvr, served}
+}
+
+type offlineResource struct {
+	dynamic.NamespaceableResourceInterface
+	gvr    schema.GroupVersionResource
+	served bool
+}
+
+func (r offlineResource) Namespace(namespace string) dynamic.ResourceInterface {
+	return offlineNamespacedResource{r.NamespaceableResourceInterface.Namespace(namespace), r.gvr, r.served}
+}
+
+func (r offlineResource) List(ctx context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	return listOffline(ctx, r.NamespaceableResourceInterface, r.gvr, r.served, opts)
+}
+
+type offlineNamespacedResource struct {
+	dynamic.ResourceInterface
+	gvr    schema.GroupVersionResource
+	served bool
+}
+
+func (r offlineNamespacedResource) List(ctx context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	return listOffline(ctx, r.ResourceInterface, r.gvr, r.served, opts)
+}
+
+// listOffline lists all objects from the fake client, then applies the field selector and paging
+// The continue token is the offset of the next item
+func listOffline(ctx context.Context, resource dynamic.ResourceInterface, gvr schema.GroupVersionResource, served bool, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	if !served {
+		return nil, apierrors.NewNotFound(gvr.GroupResource(), "")
+	}
+
+	selector := fields.Everything()
+	if opts.FieldSelector != "" {
+		var err error
+		if selector, err = fields.ParseSelector(opts.FieldSelector); err != nil {
+			return nil, apierrors.NewBadRequest(fmt.Sprintf("invalid field selector: %v", err))
+		}
+	}
+
+	offset := 0
+	if opts.Continue != "" {
+		var err error
+		if offset, err = strconv.Atoi(opts.Continue); err != nil || offset < 0 {
+			return nil, apierrors.NewBadRequest("invalid continue token")
+		}
+	}
+
+	list, err := resource.List(ctx, metav1.ListOptions{LabelSelector: opts.LabelSelector})
+	if err != nil {
+		return nil, err
+	}
+
+	items := list.Items[:0]
+	for _, item := range list.Items {
+		if selector.Matches(fields.Set{"metadata.name": item.GetName(), "metadata.namespace": item.GetNamespace()}) {
+			items = append(items, item)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].GetNamespace() != items[j].GetNamespace() {
+			return items[i].GetNamespace() < items[j].GetNamespace()
+		}
+		return items[i].GetName() < items[j].GetName()
+	})
+
+	if offset > len(items) {
+		offset = len(items)
+	}
+	items = items[offset:]
+	list.SetContinue("")
+	if opts.Limit > 0 && int64(len(items)) > opts.Limit {
+		items = items[:opts.Limit]
+		list.SetContinue(strconv.Itoa(offset + int(opts.Limit)))
+	}
+	list.Items = items
+
+	return list, nil
+}
@@ -0,0 +1,65 @@
+package k8s
+
+import (
+	"context"
+	"io"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ResourceReader is the read access to Crossplane objects and API discovery used by the API handlers
+// Client implements it for a live cluster, a bundle (NewOfflineClient) or fake clients (NewFakeClient)
+type ResourceReader interface {
+	// ListProviders returns all Provider resources
+	ListProviders(ctx context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error)
+	// ListProviderRevisions returns all ProviderRevision resources
+	ListProviderRevisions(ctx context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error)
+	// ListProviderConfigs returns all ProviderConfigs of the given GVR
+	ListProviderConfigs(ctx context.Context, gvr schema.GroupVersionResource, opts metav1.ListOptions) (*unstructured.UnstructuredList, error)
+	// ListXRDs returns all CompositeResourceDefinitions
+	ListXRDs(ctx context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error)
+	// ListCompositions returns all Compositions
+	ListCompositions(ctx context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error)
+	// ListFunctions returns all Functions
+	ListFunctions(ctx context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error)
+	// ListXRs returns the composite resources or claims of the given GVR, in all namespaces when namespace is empty
+	ListXRs(ctx context.Context, gvr schema.GroupVersionResource, namespace string, opts metav1.ListOptions) (*unstructured.UnstructuredList, error)
+	// ListResources returns the objects of any other GVR, in all namespaces when namespace is empty
+	ListResources(ctx context.Context, gvr schema.GroupVersionResource, namespace string, opts metav1.ListOptions) (*unstructured.UnstructuredList, error)
+	// GetResource returns an object, namespace is empty for cluster-scoped objects
+	GetResource(ctx context.Context, gvr schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, error)
+	// ListDeployments returns the Deployments of a namespace, in all namespaces when namespace is empty
+	ListDeployments(ctx context.Context, namespace string, opts metav1.ListOptions) (*appsv1.DeploymentList, error)
+	// ListPods returns the Pods of a namespace, in all namespaces when namespace is empty
+	ListPods(ctx context.Context, namespace string, opts metav1.ListOptions) (*corev1.PodList, error)
+	// StreamPodLogs opens the log stream of a pod container
+	StreamPodLogs(ctx context.Context, namespace, name string, opts *corev1.PodLogOptions) (io.ReadCloser, error)
+
+	// DiscoverProviderConfigGVRs returns the ProviderConfig GVRs of all installed providers
+	DiscoverProviderConfigGVRs(ctx context.Context) ([]schema.GroupVersionResource, error)
+	// DiscoverXRDGVRs returns the composite resource GVRs defined by XRDs
+	DiscoverXRDGVRs(ctx context.Context) ([]schema.GroupVersionResource, error)
+	// DiscoverClaimGVRs returns the claim GVRs defined by XRDs
+	DiscoverClaimGVRs(ctx context.Context) ([]schema.GroupVersionResource, error)
+	// DiscoverManagedResourceGVRs returns the managed resource GVRs
+	DiscoverManagedResourceGVRs(ctx context.Context) ([]schema.GroupVersionResource, error)
+	// DiscoverUsageGVRs returns the Usage and ClusterUsage GVRs
+	DiscoverUsageGVRs(ctx context.Context) ([]schema.GroupVersionResource, error)
+	// IsClusterScoped reports whether a resource is cluster-scoped
+	IsClusterScoped(ctx context.Context, gvr schema.GroupVersionResource) (bool, error)
+	// ResolveResource resolves a user-provided kind to a GVR and its scope
+	ResolveResource(ctx context.Context, kind string) (schema.GroupVersionResource, bool, error)
+	// KindFor returns the kind served by a resource
+	KindFor(gvr schema.GroupVersionResource) (schema.GroupVersionKind, error)
+}
+
+var _ ResourceReader = &Client{}
+
+// KindFor returns the kind served by a resource, resolved through the cached REST mapper
+func (c *Client) KindFor(gvr schema.GroupVersionResource) (schema.GroupVersionKind, error) {
+	return c.Mapper.KindFor(gvr)
+}
@@ -38,49 +38,91 @@ var (
 		Resource: "compositions",
 	}
 
+	// CompositionRevision is an immutable snapshot of a Composition, created on every change
+	CompositionRevisionGVR = schema.GroupVersionResource{
+		Group:    "apiextensions.crossplane.io",
+		Version:  "v1",
+		Resource: "compositionrevisions",
+	}
+
+	// CustomResourceDefinition defines a custom resource type, watched to invalidate discovery
+	CRDGVR = schema.GroupVersionResource{
+		Group:    "apiextensions.k8s.io",
+		Version:  "v1",
+		Resource: "customresourcedefinitions",
+	}
+
 	// Function defines a composition function
 	FunctionGVR = schema.GroupVersionResource{
 		Group:    "pkg.crossplane.io",
 		Version:  "v1beta1",
 		Resource: "functions",
 	}
+
+	// ProviderRevision is a revision of a Provider package, it lists the CRDs it installs
+	ProviderRevisionGVR = schema.GroupVersionResource{
+		Group:    "pkg.crossplane.io",
+		Version:  "v1",
+		Resource: "providerrevisions",
+	}
+
+	// DeploymentRuntimeConfig configures the Deployment running a Provider or Function package
+	DeploymentRuntimeConfigGVR = schema.GroupVersionResource{
+		Group:    "pkg.crossplane.io",
+		Version:  "v1beta1",
+		Resource: "deploymentruntimeconfigs",
+	}
 )
 
 // ListProviders returns all Provider resources in the cluster
-func (c *Client) ListProviders(ctx context.Context) (*unstructured.UnstructuredList, error) {
-	return c.DynamicClient.Resource(ProviderGVR).List(ctx, metav1.ListOptions{})
+// Label and field selectors in opts are applied by the API server
+func (c *Client) ListProviders(ctx context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	return c.DynamicClient.Resource(ProviderGVR).List(ctx, opts)
+}
+
+// ListProviderRevisions returns all ProviderRevision resources in the cluster
+func (c *Client) ListProviderRevisions(ctx context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	return c.DynamicClient.Resource(ProviderRevisionGVR).List(ctx, opts)
 }
 
 // ListProviderConfigs returns all ProviderConfig resources
 // Note: This is a generic method - specific provider configs may have different GVRs
-func (c *Client) ListProviderConfigs(ctx context.Context, gvr schema.GroupVersionResource) (*unstructured.UnstructuredList, error) {
-	return c.DynamicClient.Resource(gvr).List(ctx, metav1.ListOptions{})
+func (c *Client) ListProviderConfigs(ctx context.Context, gvr schema.GroupVersionResource, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	return c.DynamicClient.Resource(gvr).List(ctx, opts)
 }
 
 // ListXRDs returns all CompositeResourceDefinition resources
-func (c *Client) ListXRDs(ctx context.Context) (*unstructured.UnstructuredList, error) {
-	return c.DynamicClient.Resource(XRDGVR).List(ctx, metav1.ListOptions{})
+func (c *Client) ListXRDs(ctx context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	return c.DynamicClient.Resource(XRDGVR).List(ctx, opts)
 }
 
 // ListCompositions returns all Composition resources
-func (c *Client) ListCompositions(ctx context.Context) (*unstructured.UnstructuredList, error) {
-	return c.DynamicClient.Resource(CompositionGVR).List(ctx, metav1.ListOptions{})
+func (c *Client) ListCompositions(ctx context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	return c.DynamicClient.Resource(CompositionGVR).List(ctx, opts)
 }
 
 // ListFunctions returns all Function resources
-func (c *Client) ListFunctions(ctx context.Context) (*unstructured.UnstructuredList, error) {
-	return c.DynamicClient.Resource(FunctionGVR).List(ctx, metav1.ListOptions{})
+func (c *Client) ListFunctions(ctx context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	return c.DynamicClient.Resource(FunctionGVR).List(ctx, opts)
 }
 
 // ListXRs returns all composite resource instances for a given XRD
 // This requires the GVR to be determined from the XRD
-func (c *Client) ListXRs(ctx context.Context, gvr schema.GroupVersionResource, namespace string) (*unstructured.UnstructuredList, error) {
+func (c *Client) ListXRs(ctx context.Context, gvr schema.GroupVersionResource, namespace string, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
 	if namespace == "" {
 		// Cluster-scoped composite resources
-		return c.DynamicClient.Resource(gvr).List(ctx, metav1.ListOptions{})
+		return c.DynamicClient.Resource(gvr).List(ctx, opts)
 	}
 	// Namespace-scoped composite resources
-	return c.DynamicClient.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	return c.DynamicClient.Resource(gvr).Namespace(namespace).List(ctx, opts)
+}
+
+// ListResources returns the objects of any GVR, in all namespaces when namespace is empty
+func (c *Client) ListResources(ctx context.Context, gvr schema.GroupVersionResource, namespace string, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	if namespace == "" {
+		return c.DynamicClient.Resource(gvr).List(ctx, opts)
+	}
+	return c.DynamicClient.Resource(gvr).Namespace(namespace).List(ctx, opts)
 }
 
 // GetResource returns a specific resource by GVR, namespace, and name
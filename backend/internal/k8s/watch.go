@@ -0,0 +1,101 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/metadata/metadatainformer"
+	"k8s.io/client-go/tools/cache"
+)
+
+// StartDiscoveryWatch watches CRDs and XRDs and invalidates the discovery cache
+// whenever a type is added, removed or changes its served versions
+// Only the metadata of the definitions is watched, their schemas are not needed
+// The watch runs until the context is cancelled
+func (c *Client) StartDiscoveryWatch(ctx context.Context) error {
+	// Offline and fake clients serve a fixed set of types
+	if c.Config == nil {
+		return nil
+	}
+
+	metadataClient, err := metadata.NewForConfig(c.Config)
+	if err != nil {
+		return fmt.Errorf("failed to create metadata client: %w", err)
+	}
+	factory := metadatainformer.NewSharedInformerFactory(metadataClient, 0)
+
+	for _, gvr := range []schema.GroupVersionResource{CRDGVR, XRDGVR} {
+		informer := factory.ForResource(gvr).Informer()
+		_, err := informer.AddEventHandler(cache.ResourceEventHandlerDetailedFuncs{
+			AddFunc: func(obj interface{}, isInInitialList bool) {
+				// Objects present at startup are already part of the discovery data
+				if !isInInitialList {
+					c.InvalidateDiscovery()
+				}
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				if discoveryChanged(oldObj, newObj) {
+					c.InvalidateDiscovery()
+				}
+			},
+			DeleteFunc: func(obj interface{}) {
+				c.InvalidateDiscovery()
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to watch %s: %w", gvr.Resource, err)
+		}
+	}
+
+	factory.Start(ctx.Done())
+
+	go func() {
+		for gvr, synced := range factory.WaitForCacheSync(ctx.Done()) {
+			if !synced {
+				log.Printf("Discovery watch for %v did not sync", gvr)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// eventResyncPeriod is how often the Event watch replays the Events it holds,
+// so that Events about objects not yet observed by the handler are handled again
+const eventResyncPeriod = 5 * time.Minute
+
+// StartEventWatch watches the Kubernetes Events of all namespaces and passes them to the handler
+// The watch runs until the context is cancelled
+func (c *Client) StartEventWatch(ctx context.Context, handler cache.ResourceEventHandler) error {
+	factory := informers.NewSharedInformerFactory(c.Clientset, eventResyncPeriod)
+
+	if _, err := factory.Core().V1().Events().Informer().AddEventHandler(handler); err != nil {
+		return fmt.Errorf("failed to watch events: %w", err)
+	}
+
+	factory.Start(ctx.Done())
+	return nil
+}
+
+// discoveryChanged reports whether an update can change what the API server serves
+// Conditions are not part of the metadata, so any new resource version counts: definitions
+// only update their status when a condition such as Established flips, and relists deliver
+// updates with an unchanged resource version
+func discoveryChanged(oldObj, newObj interface{}) bool {
+	oldMeta, ok := oldObj.(*metav1.PartialObjectMetadata)
+	if !ok {
+		return true
+	}
+	newMeta, ok := newObj.(*metav1.PartialObjectMetadata)
+	if !ok {
+		return true
+	}
+
+	return oldMeta.GetResourceVersion() != newMeta.GetResourceVersion()
+}
@@ -0,0 +1,34 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ErrLogsUnavailable is returned when streaming pod logs from an offline client, bundles do not contain logs
+var ErrLogsUnavailable = errors.New("pod logs are not available in offline mode")
+
+// ListDeployments returns the Deployments of a namespace, in all namespaces when namespace is empty
+// Used to inspect the Deployments running Provider and Function packages
+func (c *Client) ListDeployments(ctx context.Context, namespace string, opts metav1.ListOptions) (*appsv1.DeploymentList, error) {
+	return c.Clientset.AppsV1().Deployments(namespace).List(ctx, opts)
+}
+
+// ListPods returns the Pods of a namespace, in all namespaces when namespace is empty
+func (c *Client) ListPods(ctx context.Context, namespace string, opts metav1.ListOptions) (*corev1.PodList, error) {
+	return c.Clientset.CoreV1().Pods(namespace).List(ctx, opts)
+}
+
+// StreamPodLogs opens the log stream of a pod container, the caller must close it
+// The stream ends with ctx when opts.Follow is set
+func (c *Client) StreamPodLogs(ctx context.Context, namespace, name string, opts *corev1.PodLogOptions) (io.ReadCloser, error) {
+	if c.Offline {
+		return nil, ErrLogsUnavailable
+	}
+	return c.Clientset.CoreV1().Pods(namespace).GetLogs(name, opts).Stream(ctx)
+}
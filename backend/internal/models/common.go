@@ -43,6 +43,18 @@ type BaseResource struct {
 	Scope      ResourceScope `json:"scope"`
 }
 
+// Resource is implemented by every converted Crossplane resource
+// It gives generic code (filtering, sorting) access to the common fields
+type Resource interface {
+	GetBaseResource() BaseResource
+	GetResourceStatus() ResourceStatus
+}
+
+// GetBaseResource returns the common resource fields
+func (b BaseResource) GetBaseResource() BaseResource {
+	return b
+}
+
 // ResourceReference represents a reference to another resource
 type ResourceReference struct {
 	Kind      string `json:"kind"`
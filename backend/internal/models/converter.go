@@ -107,6 +107,43 @@ func IsXRDEstablished(conditions []Condition) bool {
 	return IsConditionTrue(conditions, "Established")
 }
 
+// DefaultDeletionPolicy is the deletion policy of a managed resource when unset in its spec
+const DefaultDeletionPolicy = "Delete"
+
+// DefaultManagementPolicies are the management policies of a managed resource when unset in its spec
+var DefaultManagementPolicies = []string{"*"}
+
+// ConvertManagedPolicies extracts spec.managementPolicies and spec.deletionPolicy of a managed resource,
+// empty when unset
+func ConvertManagedPolicies(obj *unstructured.Unstructured) (managementPolicies []string, deletionPolicy string) {
+	managementPolicies, _, _ = unstructured.NestedStringSlice(obj.Object, "spec", "managementPolicies")
+	deletionPolicy, _, _ = unstructured.NestedString(obj.Object, "spec", "deletionPolicy")
+	return managementPolicies, deletionPolicy
+}
+
+// PausedAnnotation pauses the reconciliation of a Crossplane object when set to "true"
+const PausedAnnotation = "crossplane.io/paused"
+
+// ExternalNameAnnotation holds the name of the external resource of a managed resource
+const ExternalNameAnnotation = "crossplane.io/external-name"
+
+// ReasonReconcilePaused is the reason of the Synced condition of objects whose reconciliation is paused
+const ReasonReconcilePaused = "ReconcilePaused"
+
+// IsPaused checks if the reconciliation of an object is paused, from the crossplane.io/paused annotation
+// or the ReconcilePaused reason of its Synced condition (set until the controller observes the unpause)
+func IsPaused(obj *unstructured.Unstructured, conditions []Condition) bool {
+	if obj.GetAnnotations()[PausedAnnotation] == "true" {
+		return true
+	}
+	for _, c := range conditions {
+		if c.Type == "Synced" && c.Reason == ReasonReconcilePaused {
+			return true
+		}
+	}
+	return false
+}
+
 // ConvertToResourceStatus extracts status from an unstructured object
 func ConvertToResourceStatus(obj *unstructured.Unstructured) ResourceStatus {
 	status, found, _ := unstructured.NestedMap(obj.Object, "status")
@@ -124,6 +161,15 @@ func ConvertToResourceStatus(obj *unstructured.Unstructured) ResourceStatus {
 	}
 }
 
+// ConvertToProviderConfigStatus extracts the status of a ProviderConfig, which reports its users instead of conditions
+func ConvertToProviderConfigStatus(obj *unstructured.Unstructured) ProviderConfigStatus {
+	status, _, _ := unstructured.NestedMap(obj.Object, "status")
+	return ProviderConfigStatus{
+		ResourceStatus: ConvertToResourceStatus(obj),
+		Users:          getIntField(status, "users"),
+	}
+}
+
 // Helper function to safely get string field
 func getStringField(obj map[string]interface{}, field string) string {
 	if val, ok := obj[field].(string); ok {
@@ -159,3 +205,133 @@ func ConvertTimeString(timeStr string) time.Time {
 	}
 	return t
 }
+
+// ConvertToProvider converts a Provider, which is ready when both installed and healthy
+func ConvertToProvider(obj *unstructured.Unstructured) Provider {
+	resourceStatus := ConvertToResourceStatus(obj)
+	installed, healthy := IsProviderHealthy(resourceStatus.Conditions)
+	resourceStatus.Ready = installed && healthy
+
+	pkg, _, _ := unstructured.NestedString(obj.Object, "spec", "package")
+
+	return Provider{
+		BaseResource: ConvertToBaseResource(obj, ScopeCluster),
+		Spec: ProviderSpec{
+			Package: pkg,
+		},
+		Status: ProviderStatus{
+			ResourceStatus: resourceStatus,
+			Installed:      installed,
+			Healthy:        healthy,
+		},
+	}
+}
+
+// ConvertToProviderConfig converts a ProviderConfig or ClusterProviderConfig
+func ConvertToProviderConfig(obj *unstructured.Unstructured) ProviderConfig {
+	scope := ScopeCluster
+	if obj.GetNamespace() != "" {
+		scope = ScopeNamespace
+	}
+	source, _, _ := unstructured.NestedString(obj.Object, "spec", "credentials", "source")
+
+	config := ProviderConfig{
+		BaseResource: ConvertToBaseResource(obj, scope),
+		Status:       ConvertToProviderConfigStatus(obj),
+		Spec: ProviderConfigSpec{
+			CredentialsSource: source,
+		},
+	}
+	if name, _, _ := unstructured.NestedString(obj.Object, "spec", "credentials", "secretRef", "name"); name != "" {
+		namespace, _, _ := unstructured.NestedString(obj.Object, "spec", "credentials", "secretRef", "namespace")
+		key, _, _ := unstructured.NestedString(obj.Object, "spec", "credentials", "secretRef", "key")
+		config.Spec.CredentialsSecretRef = &SecretReference{Name: name, Namespace: namespace, Key: key}
+	}
+	return config
+}
+
+// ConvertToXRD converts a CompositeResourceDefinition, which is ready when established
+func ConvertToXRD(obj *unstructured.Unstructured) XRD {
+	resourceStatus := ConvertToResourceStatus(obj)
+	established := IsXRDEstablished(resourceStatus.Conditions)
+	resourceStatus.Ready = established
+
+	group, _, _ := unstructured.NestedString(obj.Object, "spec", "group")
+
+	return XRD{
+		BaseResource: ConvertToBaseResource(obj, ScopeCluster),
+		Spec: XRDSpec{
+			Group: group,
+		},
+		Status: XRDStatus{
+			ResourceStatus: resourceStatus,
+			Established:    established,
+		},
+	}
+}
+
+// ConvertToComposition converts a Composition
+func ConvertToComposition(obj *unstructured.Unstructured) Composition {
+	apiVersion, _, _ := unstructured.NestedString(obj.Object, "spec", "compositeTypeRef", "apiVersion")
+	kind, _, _ := unstructured.NestedString(obj.Object, "spec", "compositeTypeRef", "kind")
+
+	return Composition{
+		BaseResource: ConvertToBaseResource(obj, ScopeCluster),
+		Spec: CompositionSpec{
+			CompositeTypeRef: TypeReference{
+				APIVersion: apiVersion,
+				Kind:       kind,
+			},
+		},
+		Status: CompositionStatus{ResourceStatus: ConvertToResourceStatus(obj)},
+	}
+}
+
+// ConvertToFunction converts a Function, which is ready when both installed and healthy
+func ConvertToFunction(obj *unstructured.Unstructured) Function {
+	resourceStatus := ConvertToResourceStatus(obj)
+	installed, healthy := IsFunctionHealthy(resourceStatus.Conditions)
+	resourceStatus.Ready = installed && healthy
+
+	pkg, _, _ := unstructured.NestedString(obj.Object, "spec", "package")
+
+	return Function{
+		BaseResource: ConvertToBaseResource(obj, ScopeCluster),
+		Spec: FunctionSpec{
+			Package: pkg,
+		},
+		Status: FunctionStatus{
+			ResourceStatus: resourceStatus,
+			Installed:      installed,
+			Healthy:        healthy,
+		},
+	}
+}
+
+// ConvertToCompositeResource converts a composite resource, a claim or a managed resource
+func ConvertToCompositeResource(obj *unstructured.Unstructured) CompositeResource {
+	scope := ScopeCluster
+	if obj.GetNamespace() != "" {
+		scope = ScopeNamespace
+	}
+	resourceStatus := ConvertToResourceStatus(obj)
+	compositionRef, revisionRef, updatePolicy := ConvertCompositionPinning(obj)
+	var compositionSelector *map[string]string
+	if selector := ConvertCompositionSelector(obj); selector != nil {
+		compositionSelector = &selector
+	}
+
+	return CompositeResource{
+		BaseResource: ConvertToBaseResource(obj, scope),
+		Spec: CompositeResourceSpec{
+			CompositionRef:          compositionRef,
+			CompositionSelector:     compositionSelector,
+			CompositionRevisionRef:  revisionRef,
+			CompositionUpdatePolicy: updatePolicy,
+		},
+		Status: CompositeResourceStatus{
+			ResourceStatus: resourceStatus,
+			Paused:         IsPaused(obj, resourceStatus.Conditions),
+		},
+	}
+}
@@ -0,0 +1,123 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// update rewrites the golden files with the current output: go test ./internal/models -update
+var update = flag.Bool("update", false, "update the golden files")
+
+// converters maps a kind to the converter used by its list endpoint
+// Other kinds (composite resources, claims, managed resources) use ConvertToCompositeResource
+var converters = map[string]func(*unstructured.Unstructured) interface{}{
+	"Provider":                    func(obj *unstructured.Unstructured) interface{} { return ConvertToProvider(obj) },
+	"ProviderConfig":              func(obj *unstructured.Unstructured) interface{} { return ConvertToProviderConfig(obj) },
+	"CompositeResourceDefinition": func(obj *unstructured.Unstructured) interface{} { return ConvertToXRD(obj) },
+	"Composition":                 func(obj *unstructured.Unstructured) interface{} { return ConvertToComposition(obj) },
+	"Function":                    func(obj *unstructured.Unstructured) interface{} { return ConvertToFunction(obj) },
+	"Usage":                       func(obj *unstructured.Unstructured) interface{} { return ConvertToUsage(obj) },
+	"ClusterUsage":                func(obj *unstructured.Unstructured) interface{} { return ConvertToUsage(obj) },
+	"EnvironmentConfig":           func(obj *unstructured.Unstructured) interface{} { return ConvertToEnvironmentConfig(obj) },
+}
+
+// TestConvertersGolden converts every object of the fixture corpus (testdata/crossplane) with the converter
+// of its kind and compares the JSON output with testdata/golden
+func TestConvertersGolden(t *testing.T) {
+	// Timestamps are decoded in the local time zone
+	local := time.Local
+	time.Local = time.UTC
+	t.Cleanup(func() { time.Local = local })
+
+	fixtures, err := filepath.Glob("testdata/crossplane/*.yaml")
+	if err != nil || len(fixtures) == 0 {
+		t.Fatalf("no fixtures found: %v", err)
+	}
+
+	for _, fixture := range fixtures {
+		name := strings.TrimSuffix(filepath.Base(fixture), ".yaml")
+		t.Run(name, func(t *testing.T) {
+			got := []interface{}{}
+			for _, obj := range loadFixture(t, fixture) {
+				convert, ok := converters[obj.GetKind()]
+				if !ok {
+					convert = func(obj *unstructured.Unstructured) interface{} { return ConvertToCompositeResource(obj) }
+				}
+				got = append(got, convert(obj))
+			}
+
+			assertGolden(t, filepath.Join("testdata", "golden", name+".json"), got)
+		})
+	}
+}
+
+// TestEnvironmentSelectorsGolden converts the EnvironmentConfig selectors of the corpus Composition
+func TestEnvironmentSelectorsGolden(t *testing.T) {
+	composition := loadFixture(t, "testdata/crossplane/composition-environment.yaml")[0]
+	assertGolden(t, filepath.Join("testdata", "golden", "composition-environment-selectors.json"), ConvertEnvironmentSelectors(composition))
+}
+
+// loadFixture reads the objects of a multi-document YAML file
+func loadFixture(t *testing.T, path string) []*unstructured.Unstructured {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open %s: %v", path, err)
+	}
+	defer f.Close()
+
+	var objs []*unstructured.Unstructured
+	decoder := utilyaml.NewYAMLOrJSONDecoder(f, 4096)
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if err == io.EOF {
+				return objs
+			}
+			t.Fatalf("failed to decode %s: %v", path, err)
+		}
+		if obj.Object != nil {
+			objs = append(objs, obj)
+		}
+	}
+}
+
+// assertGolden compares the indented JSON encoding of got with a golden file
+func assertGolden(t *testing.T, path string, got interface{}) {
+	t.Helper()
+
+	data, err := json.MarshalIndent(got, "", "  ")
+	if err != nil {
+		t.Fatalf("failed to encode output: %v", err)
+	}
+	data = append(data, '\n')
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file, run with -update to create it: %v", err)
+	}
+	if !bytes.Equal(data, want) {
+		t.Errorf("output differs from %s, run with -update if the change is expected\ngot:\n%s\nwant:\n%s", path, data, want)
+	}
+}
@@ -0,0 +1,182 @@
+package models
+
+import (
+	"sort"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Environment selector types and modes, with the defaults Crossplane applies when they are unset
+const (
+	EnvironmentSourceReference = "Reference"
+	EnvironmentSourceSelector  = "Selector"
+
+	EnvironmentSelectorSingle   = "Single"
+	EnvironmentSelectorMultiple = "Multiple"
+
+	EnvironmentLabelFromCompositeFieldPath = "FromCompositeFieldPath"
+	EnvironmentLabelValue                  = "Value"
+
+	DefaultEnvironmentSortByFieldPath = "metadata.name"
+)
+
+// EnvironmentConfigsFunctionGroup is the input group of function-environment-configs,
+// which selects EnvironmentConfigs in Pipeline mode Compositions
+const EnvironmentConfigsFunctionGroup = "environmentconfigs.fn.crossplane.io"
+
+// EnvironmentConfig represents an EnvironmentConfig, a cluster-scoped map of data shared by Compositions
+type EnvironmentConfig struct {
+	BaseResource
+	DataKeys []string `json:"dataKeys"`
+	// Data is only returned by the detail endpoint
+	Data map[string]interface{} `json:"data,omitempty"`
+}
+
+// GetResourceStatus returns the common status fields
+// EnvironmentConfigs have no status, they are usable once created
+func (e EnvironmentConfig) GetResourceStatus() ResourceStatus {
+	return ResourceStatus{Ready: true}
+}
+
+// ConvertToEnvironmentConfig converts an EnvironmentConfig, without its data
+func ConvertToEnvironmentConfig(obj *unstructured.Unstructured) EnvironmentConfig {
+	config := EnvironmentConfig{
+		BaseResource: ConvertToBaseResource(obj, ScopeCluster),
+		DataKeys:     []string{},
+	}
+	data, _, _ := unstructured.NestedMap(obj.Object, "data")
+	for key := range data {
+		config.DataKeys = append(config.DataKeys, key)
+	}
+	sort.Strings(config.DataKeys)
+	return config
+}
+
+// CompositionDetail represents a Composition with the EnvironmentConfigs it selects
+type CompositionDetail struct {
+	Composition
+	Environment []EnvironmentSelector `json:"environment"`
+}
+
+// EnvironmentSelector is an entry of the environmentConfigs of a Composition (spec.environment)
+// or of the input of a function-environment-configs pipeline step
+type EnvironmentSelector struct {
+	// Step is the pipeline step declaring the selector, empty for spec.environment
+	Step  string `json:"step,omitempty"`
+	Index int    `json:"index"`
+	Type  string `json:"type"`
+	// Ref is the EnvironmentConfig name of a Reference
+	Ref             string             `json:"ref,omitempty"`
+	Mode            string             `json:"mode,omitempty"`
+	MatchLabels     []EnvironmentLabel `json:"matchLabels,omitempty"`
+	SortByFieldPath string             `json:"sortByFieldPath,omitempty"`
+	MinMatch        *int               `json:"minMatch,omitempty"`
+	MaxMatch        *int               `json:"maxMatch,omitempty"`
+
+	// Resolution against the current EnvironmentConfigs, set by the API handlers
+	// Dynamic selectors take label values from the composite resource, their matches are the candidates
+	// having the selected label keys
+	Dynamic bool     `json:"dynamic"`
+	Matches []string `json:"matches"`
+	Problem string   `json:"problem,omitempty"`
+}
+
+// EnvironmentLabel is a label matched by an EnvironmentConfig selector
+type EnvironmentLabel struct {
+	Key                string `json:"key"`
+	Type               string `json:"type"`
+	Value              string `json:"value,omitempty"`
+	ValueFromFieldPath string `json:"valueFromFieldPath,omitempty"`
+}
+
+// ConvertEnvironmentSelectors extracts the EnvironmentConfig selectors of a Composition,
+// from spec.environment and from its function-environment-configs pipeline steps
+func ConvertEnvironmentSelectors(composition *unstructured.Unstructured) []EnvironmentSelector {
+	selectors := []EnvironmentSelector{}
+
+	configs, _, _ := unstructured.NestedSlice(composition.Object, "spec", "environment", "environmentConfigs")
+	selectors = append(selectors, convertEnvironmentConfigs("", configs)...)
+
+	pipeline, _, _ := unstructured.NestedSlice(composition.Object, "spec", "pipeline")
+	for _, s := range pipeline {
+		step, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		apiVersion, _, _ := unstructured.NestedString(step, "input", "apiVersion")
+		if gv, err := schema.ParseGroupVersion(apiVersion); err != nil || gv.Group != EnvironmentConfigsFunctionGroup {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(step, "step")
+		configs, _, _ := unstructured.NestedSlice(step, "input", "spec", "environmentConfigs")
+		selectors = append(selectors, convertEnvironmentConfigs(name, configs)...)
+	}
+
+	return selectors
+}
+
+func convertEnvironmentConfigs(step string, configs []interface{}) []EnvironmentSelector {
+	var selectors []EnvironmentSelector
+	for i, c := range configs {
+		config, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		selector := EnvironmentSelector{Step: step, Index: i, Type: EnvironmentSourceReference, Matches: []string{}}
+		if t, _, _ := unstructured.NestedString(config, "type"); t != "" {
+			selector.Type = t
+		}
+
+		if selector.Type == EnvironmentSourceReference {
+			selector.Ref, _, _ = unstructured.NestedString(config, "ref", "name")
+			selectors = append(selectors, selector)
+			continue
+		}
+
+		selector.Mode, _, _ = unstructured.NestedString(config, "selector", "mode")
+		if selector.Mode == "" {
+			selector.Mode = EnvironmentSelectorSingle
+		}
+		selector.SortByFieldPath, _, _ = unstructured.NestedString(config, "selector", "sortByFieldPath")
+		if selector.SortByFieldPath == "" {
+			selector.SortByFieldPath = DefaultEnvironmentSortByFieldPath
+		}
+		selector.MinMatch = getOptionalInt(config, "selector", "minMatch")
+		selector.MaxMatch = getOptionalInt(config, "selector", "maxMatch")
+
+		labels, _, _ := unstructured.NestedSlice(config, "selector", "matchLabels")
+		for _, l := range labels {
+			label, ok := l.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			var match EnvironmentLabel
+			match.Key, _, _ = unstructured.NestedString(label, "key")
+			match.Type, _, _ = unstructured.NestedString(label, "type")
+			if match.Type == "" {
+				match.Type = EnvironmentLabelFromCompositeFieldPath
+			}
+			match.Value, _, _ = unstructured.NestedString(label, "value")
+			match.ValueFromFieldPath, _, _ = unstructured.NestedString(label, "valueFromFieldPath")
+			selector.MatchLabels = append(selector.MatchLabels, match)
+		}
+
+		selectors = append(selectors, selector)
+	}
+	return selectors
+}
+
+// getOptionalInt returns a nested integer, nil when unset
+func getOptionalInt(obj map[string]interface{}, fields ...string) *int {
+	parent, found, _ := unstructured.NestedMap(obj, fields[:len(fields)-1]...)
+	if !found {
+		return nil
+	}
+	if _, ok := parent[fields[len(fields)-1]]; !ok {
+		return nil
+	}
+	n := getIntField(parent, fields[len(fields)-1])
+	return &n
+}
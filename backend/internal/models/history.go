@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// ConditionTransition represents a change of a status condition observed on a resource
+type ConditionTransition struct {
+	Type               string    `json:"type"`
+	Status             string    `json:"status"`
+	PreviousStatus     string    `json:"previousStatus,omitempty"`
+	Reason             string    `json:"reason,omitempty"`
+	Message            string    `json:"message,omitempty"`
+	LastTransitionTime time.Time `json:"lastTransitionTime"`
+	ObservedAt         time.Time `json:"observedAt"`
+}
+
+// ObjectEvent represents a Kubernetes Event about a resource, recorded again each time its count increases
+type ObjectEvent struct {
+	UID            string    `json:"uid"`
+	Type           string    `json:"type"`
+	Reason         string    `json:"reason"`
+	Message        string    `json:"message,omitempty"`
+	Source         string    `json:"source,omitempty"`
+	Count          int32     `json:"count"`
+	FirstTimestamp time.Time `json:"firstTimestamp"`
+	LastTimestamp  time.Time `json:"lastTimestamp"`
+	ObservedAt     time.Time `json:"observedAt"`
+}
+
+// ResourceHistory represents the condition timeline of a resource and the Events recorded about it
+// Count is the number of transitions
+type ResourceHistory struct {
+	Group       string                `json:"group"`
+	Kind        string                `json:"kind"`
+	Namespace   string                `json:"namespace,omitempty"`
+	Name        string                `json:"name"`
+	Count       int                   `json:"count"`
+	Transitions []ConditionTransition `json:"transitions"`
+	Events      []ObjectEvent         `json:"events"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Severities of composition lint findings
+const (
+	// LintError findings break the composition of its composite resources
+	LintError = "Error"
+	// LintWarning findings are likely mistakes that Crossplane does not reject
+	LintWarning = "Warning"
+)
+
+// CompositionLintReport lists the findings of the static checks of a Composition
+// CrossplaneV2 reports whether the cluster serves the Crossplane v2 APIs, which changes the legacy checks
+type CompositionLintReport struct {
+	Kind          string        `json:"kind"`
+	GeneratedAt   time.Time     `json:"generatedAt"`
+	CacheSynced   bool          `json:"cacheSynced"`
+	Composition   string        `json:"composition"`
+	CompositeType TypeReference `json:"compositeType"`
+	Mode          string        `json:"mode"`
+	CrossplaneV2  bool          `json:"crossplaneV2"`
+	Errors        int           `json:"errors"`
+	Warnings      int           `json:"warnings"`
+	Findings      []LintFinding `json:"findings"`
+}
+
+// LintFinding represents a failed check, Path locates the offending field in the Composition
+type LintFinding struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Path     string `json:"path"`
+	Message  string `json:"message"`
+}
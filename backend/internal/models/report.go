@@ -0,0 +1,193 @@
+package models
+
+import "time"
+
+// ObjectRef identifies an object listed in a report
+type ObjectRef struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+// SyncFailureReport groups the managed resources that fail to sync,
+// and lists the ready ones whose spec keeps being updated
+type SyncFailureReport struct {
+	Kind          string             `json:"kind"`
+	GeneratedAt   time.Time          `json:"generatedAt"`
+	CacheSynced   bool               `json:"cacheSynced"`
+	ManagedCount  int                `json:"managedCount"`
+	UnsyncedCount int                `json:"unsyncedCount"`
+	Groups        []SyncFailureGroup `json:"groups"`
+	Drifting      []DriftingResource `json:"drifting"`
+}
+
+// SyncFailureGroup represents the unsynced managed resources of a provider and kind failing with the same error
+// Message is normalized (names, IDs and numbers replaced), SampleMessage is one of the original messages
+type SyncFailureGroup struct {
+	Provider        string         `json:"provider"`
+	Group           string         `json:"group"`
+	Kind            string         `json:"kind"`
+	Reason          string         `json:"reason"`
+	Message         string         `json:"message"`
+	SampleMessage   string         `json:"sampleMessage"`
+	Count           int            `json:"count"`
+	OldestFailure   time.Time      `json:"oldestFailure"`
+	ProviderConfigs map[string]int `json:"providerConfigs,omitempty"`
+	Resources       []ObjectRef    `json:"resources"`
+}
+
+// DriftingResource represents a ready managed resource whose spec was updated repeatedly in the tracking window
+type DriftingResource struct {
+	ObjectRef
+	Provider   string    `json:"provider"`
+	Updates    int       `json:"updates"`
+	LastUpdate time.Time `json:"lastUpdate"`
+}
+
+// StuckDeletionReport lists the Crossplane objects being deleted, the longest terminating first
+type StuckDeletionReport struct {
+	Kind        string                `json:"kind"`
+	GeneratedAt time.Time             `json:"generatedAt"`
+	CacheSynced bool                  `json:"cacheSynced"`
+	Count       int                   `json:"count"`
+	Resources   []TerminatingResource `json:"resources"`
+}
+
+// TerminatingResource represents an object with a deletion timestamp
+// BlockingUsages are the Usages protecting the object or one of its composed resources
+type TerminatingResource struct {
+	ObjectRef
+	Category           string          `json:"category"`
+	DeletionTimestamp  time.Time       `json:"deletionTimestamp"`
+	TerminatingSeconds int64           `json:"terminatingSeconds"`
+	Finalizers         []string        `json:"finalizers"`
+	Conditions         []Condition     `json:"conditions,omitempty"`
+	BlockingUsages     []BlockingUsage `json:"blockingUsages"`
+}
+
+// BlockingUsage represents a Usage preventing the deletion of the object it is of
+type BlockingUsage struct {
+	ObjectRef
+	Of     ObjectRef  `json:"of"`
+	By     *ObjectRef `json:"by,omitempty"`
+	Reason string     `json:"reason,omitempty"`
+}
+
+// OrphanReport lists the managed resources whose owner is missing or who have no owner,
+// and the composite resources referencing missing resources
+type OrphanReport struct {
+	Kind         string                 `json:"kind"`
+	GeneratedAt  time.Time              `json:"generatedAt"`
+	CacheSynced  bool                   `json:"cacheSynced"`
+	ManagedCount int                    `json:"managedCount"`
+	Orphaned     []OrphanedResource     `json:"orphaned"`
+	Unowned      []OrphanedResource     `json:"unowned"`
+	MissingRefs  []CompositeMissingRefs `json:"missingResourceRefs"`
+}
+
+// OrphanedResource represents a managed resource without an existing owner
+// Composite is the crossplane.io/composite label, Owner the owner reference that could not be found
+type OrphanedResource struct {
+	ObjectRef
+	Composite         string     `json:"composite,omitempty"`
+	Owner             *ObjectRef `json:"owner,omitempty"`
+	Ready             bool       `json:"ready"`
+	CreationTimestamp time.Time  `json:"creationTimestamp"`
+}
+
+// CompositeMissingRefs represents a composite resource whose resourceRefs point to objects that do not exist
+type CompositeMissingRefs struct {
+	ObjectRef
+	Missing []ObjectRef `json:"missing"`
+}
+
+// PausedReport lists the Crossplane objects whose reconciliation is paused, the longest paused first
+type PausedReport struct {
+	Kind        string           `json:"kind"`
+	GeneratedAt time.Time        `json:"generatedAt"`
+	CacheSynced bool             `json:"cacheSynced"`
+	Count       int              `json:"count"`
+	Resources   []PausedResource `json:"resources"`
+}
+
+// PausedResource represents a paused object
+// PausedBy and PausedAt come from the managedFields entry owning the annotation: PausedAt is the last time
+// that field manager updated the object, PausedSince the transition to the ReconcilePaused condition
+type PausedResource struct {
+	ObjectRef
+	Category    string     `json:"category"`
+	Annotated   bool       `json:"annotated"`
+	PausedBy    string     `json:"pausedBy,omitempty"`
+	Operation   string     `json:"operation,omitempty"`
+	PausedAt    *time.Time `json:"pausedAt,omitempty"`
+	PausedSince *time.Time `json:"pausedSince,omitempty"`
+}
+
+// PolicyAuditReport counts the managed resources per management and deletion policy combination,
+// and flags the risky ones
+type PolicyAuditReport struct {
+	Kind         string              `json:"kind"`
+	GeneratedAt  time.Time           `json:"generatedAt"`
+	CacheSynced  bool                `json:"cacheSynced"`
+	ManagedCount int                 `json:"managedCount"`
+	Combinations []PolicyCombination `json:"combinations"`
+	Findings     []PolicyFinding     `json:"findings"`
+}
+
+// PolicyCombination represents the managed resources of a provider and namespace with the same effective policies
+// DeletesExternal reports whether deleting them deletes the external resources
+type PolicyCombination struct {
+	Provider           string   `json:"provider"`
+	Namespace          string   `json:"namespace,omitempty"`
+	ManagementPolicies []string `json:"managementPolicies"`
+	DeletionPolicy     string   `json:"deletionPolicy"`
+	DeletesExternal    bool     `json:"deletesExternal"`
+	Count              int      `json:"count"`
+}
+
+// PolicyFinding represents a managed resource with a risky policy combination
+type PolicyFinding struct {
+	ObjectRef
+	Provider           string   `json:"provider"`
+	Rule               string   `json:"rule"`
+	Message            string   `json:"message"`
+	ManagementPolicies []string `json:"managementPolicies"`
+	DeletionPolicy     string   `json:"deletionPolicy"`
+}
+
+// Severities of the Crossplane v2 upgrade findings
+const (
+	// UpgradeRemoved constructs no longer work with Crossplane v2 and must change before upgrading
+	UpgradeRemoved = "Removed"
+	// UpgradeDeprecated constructs still work with Crossplane v2 as legacy features
+	UpgradeDeprecated = "Deprecated"
+)
+
+// UpgradeReport inventories the Crossplane v1 constructs deprecated or removed in Crossplane v2, grouped by object
+// Ready is set when no object uses a removed construct, Rules counts the findings per rule
+type UpgradeReport struct {
+	Kind        string          `json:"kind"`
+	GeneratedAt time.Time       `json:"generatedAt"`
+	CacheSynced bool            `json:"cacheSynced"`
+	Ready       bool            `json:"ready"`
+	Removed     int             `json:"removed"`
+	Deprecated  int             `json:"deprecated"`
+	Rules       map[string]int  `json:"rules"`
+	Objects     []UpgradeObject `json:"objects"`
+}
+
+// UpgradeObject represents an object using v1 constructs, Blocking is set when one of them is removed in v2
+type UpgradeObject struct {
+	ObjectRef
+	Blocking bool             `json:"blocking"`
+	Findings []UpgradeFinding `json:"findings"`
+}
+
+// UpgradeFinding represents a v1 construct used by an object, with the change it needs
+type UpgradeFinding struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Action   string `json:"action"`
+}
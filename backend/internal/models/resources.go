@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // Provider represents a Crossplane Provider resource
 type Provider struct {
 	BaseResource
@@ -7,6 +9,11 @@ type Provider struct {
 	Spec   ProviderSpec   `json:"spec,omitempty"`
 }
 
+// GetResourceStatus returns the common status fields
+func (r Provider) GetResourceStatus() ResourceStatus {
+	return r.Status.ResourceStatus
+}
+
 type ProviderSpec struct {
 	Package string `json:"package"`
 }
@@ -22,8 +29,42 @@ type ProviderStatus struct {
 // ProviderConfig represents a provider configuration
 type ProviderConfig struct {
 	BaseResource
-	Status ResourceStatus `json:"status"`
-	// Spec varies by provider, kept as raw for flexibility
+	Status ProviderConfigStatus `json:"status"`
+	// Spec varies by provider, only the credentials source is extracted
+	Spec ProviderConfigSpec `json:"spec"`
+	// Usages are the ProviderConfigUsages of the resources using the config
+	Usages []ProviderConfigUsage `json:"usages,omitempty"`
+}
+
+// GetResourceStatus returns the common status fields
+func (r ProviderConfig) GetResourceStatus() ResourceStatus {
+	return r.Status.ResourceStatus
+}
+
+type ProviderConfigSpec struct {
+	// CredentialsSource is spec.credentials.source (e.g. Secret, IRSA, InjectedIdentity)
+	CredentialsSource string `json:"credentialsSource,omitempty"`
+	// CredentialsSecretRef locates the credentials Secret, its contents are never read
+	CredentialsSecretRef *SecretReference `json:"credentialsSecretRef,omitempty"`
+}
+
+type ProviderConfigStatus struct {
+	ResourceStatus
+	// Users is the number of resources using the config, as counted by the provider
+	Users int `json:"users"`
+}
+
+// SecretReference represents a key of a Secret
+type SecretReference struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Key       string `json:"key,omitempty"`
+}
+
+// ProviderConfigUsage represents a ProviderConfigUsage, tracking a resource using a ProviderConfig
+type ProviderConfigUsage struct {
+	Name     string    `json:"name"`
+	Resource ObjectRef `json:"resource"`
 }
 
 // XRD represents a CompositeResourceDefinition
@@ -33,6 +74,11 @@ type XRD struct {
 	Spec   XRDSpec   `json:"spec,omitempty"`
 }
 
+// GetResourceStatus returns the common status fields
+func (r XRD) GetResourceStatus() ResourceStatus {
+	return r.Status.ResourceStatus
+}
+
 type XRDSpec struct {
 	Group            string   `json:"group"`
 	ClaimNames       *Names   `json:"claimNames,omitempty"`
@@ -69,6 +115,11 @@ type Composition struct {
 	Spec   CompositionSpec   `json:"spec,omitempty"`
 }
 
+// GetResourceStatus returns the common status fields
+func (r Composition) GetResourceStatus() ResourceStatus {
+	return r.Status.ResourceStatus
+}
+
 type CompositionSpec struct {
 	CompositeTypeRef TypeReference `json:"compositeTypeRef"`
 	Mode             string        `json:"mode,omitempty"` // Pipeline or Resources
@@ -87,6 +138,11 @@ type Function struct {
 	Spec   FunctionSpec   `json:"spec,omitempty"`
 }
 
+// GetResourceStatus returns the common status fields
+func (r Function) GetResourceStatus() ResourceStatus {
+	return r.Status.ResourceStatus
+}
+
 type FunctionSpec struct {
 	Package string `json:"package"`
 }
@@ -105,16 +161,47 @@ type CompositeResource struct {
 	Spec   CompositeResourceSpec   `json:"spec,omitempty"`
 }
 
+// GetResourceStatus returns the common status fields
+func (r CompositeResource) GetResourceStatus() ResourceStatus {
+	return r.Status.ResourceStatus
+}
+
 type CompositeResourceSpec struct {
 	CompositionRef      *ResourceReference `json:"compositionRef,omitempty"`
 	CompositionSelector *map[string]string `json:"compositionSelector,omitempty"`
 	ResourceRefs        []ResourceReference `json:"resourceRefs,omitempty"`
+	// Revision pinning of composite resources and claims
+	CompositionRevisionRef  *ResourceReference `json:"compositionRevisionRef,omitempty"`
+	CompositionUpdatePolicy string             `json:"compositionUpdatePolicy,omitempty"`
 }
 
 type CompositeResourceStatus struct {
 	ResourceStatus
+	Paused         bool                `json:"paused,omitempty"`
 	CompositionRef *ResourceReference  `json:"compositionRef,omitempty"`
 	ResourceRefs   []ResourceReference `json:"resourceRefs,omitempty"`
+	// LatestRevision is the latest CompositionRevision of the Composition, RevisionLagging is set when
+	// the composite resource uses an older one, pinned by the Manual update policy or not yet updated
+	LatestRevision  string `json:"latestRevision,omitempty"`
+	RevisionLagging bool   `json:"revisionLagging,omitempty"`
+}
+
+// ResourceDetail represents any object with its status and the Usages involving it
+type ResourceDetail struct {
+	BaseResource
+	Status            ResourceStatus `json:"status"`
+	Finalizers        []string       `json:"finalizers,omitempty"`
+	DeletionTimestamp *time.Time     `json:"deletionTimestamp,omitempty"`
+	Protection        Protection     `json:"protection"`
+	Paused            bool           `json:"paused,omitempty"`
+	// PausedReason is ReconcilePaused once the controller reports the pause on the Synced condition,
+	// empty while only the crossplane.io/paused annotation is set
+	PausedReason string `json:"pausedReason,omitempty"`
+	// Policies of managed resources, as set in their spec
+	ManagementPolicies []string `json:"managementPolicies,omitempty"`
+	DeletionPolicy     string   `json:"deletionPolicy,omitempty"`
+	// CompositionSelection is set for composite resources and claims
+	CompositionSelection *CompositionSelection `json:"compositionSelection,omitempty"`
 }
 
 // ResourceList represents a list of resources with metadata
@@ -0,0 +1,88 @@
+package models
+
+import "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+// Labels set by Crossplane on CompositionRevisions
+const (
+	CompositionNameLabel = "crossplane.io/composition-name"
+	CompositionHashLabel = "crossplane.io/composition-hash"
+)
+
+// Composition update policies of composite resources, Automatic is the default
+const (
+	CompositionUpdateAutomatic = "Automatic"
+	CompositionUpdateManual    = "Manual"
+)
+
+// CompositionRevision represents a revision of a Composition
+// Diff compares it with the previous revision, it is nil for the first one
+type CompositionRevision struct {
+	BaseResource
+	Revision  int64         `json:"revision"`
+	Hash      string        `json:"hash,omitempty"`
+	Latest    bool          `json:"latest"`
+	Mode      string        `json:"mode,omitempty"`
+	Steps     []string      `json:"steps,omitempty"`
+	Resources []string      `json:"resources,omitempty"`
+	Diff      *RevisionDiff `json:"diff,omitempty"`
+}
+
+// RevisionDiff represents the changes of a CompositionRevision since revision From
+// Pipeline steps are matched by step name, resources by name (or by index when unnamed),
+// Spec lists the changes of the other spec fields
+type RevisionDiff struct {
+	From      int64         `json:"from"`
+	Pipeline  ListDiff      `json:"pipeline"`
+	Resources ListDiff      `json:"resources"`
+	Spec      []FieldChange `json:"spec"`
+}
+
+// ListDiff represents the changes of a list of named items
+type ListDiff struct {
+	Added     []string     `json:"added"`
+	Removed   []string     `json:"removed"`
+	Changed   []ItemChange `json:"changed"`
+	Reordered bool         `json:"reordered,omitempty"`
+}
+
+// ItemChange represents the changed fields of a named item, with paths relative to the item
+type ItemChange struct {
+	Name    string        `json:"name"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// ConvertCompositionPinning extracts the Composition and CompositionRevision references and the
+// composition update policy of a composite resource or claim, under spec.crossplane for Crossplane v2 XRs
+// The update policy defaults to Automatic for objects bound to a Composition
+func ConvertCompositionPinning(obj *unstructured.Unstructured) (compositionRef, revisionRef *ResourceReference, updatePolicy string) {
+	if name, _, _ := unstructured.NestedString(obj.Object, crossplaneSpecField(obj, "compositionRef", "name")...); name != "" {
+		compositionRef = &ResourceReference{Kind: "Composition", Name: name}
+	}
+	if name, _, _ := unstructured.NestedString(obj.Object, crossplaneSpecField(obj, "compositionRevisionRef", "name")...); name != "" {
+		revisionRef = &ResourceReference{Kind: "CompositionRevision", Name: name}
+	}
+	updatePolicy, _, _ = unstructured.NestedString(obj.Object, crossplaneSpecField(obj, "compositionUpdatePolicy")...)
+	if updatePolicy == "" && compositionRef != nil {
+		updatePolicy = CompositionUpdateAutomatic
+	}
+	return compositionRef, revisionRef, updatePolicy
+}
+
+// ConvertCompositionSelector extracts the compositionSelector labels of a composite resource or claim, nil when unset
+func ConvertCompositionSelector(obj *unstructured.Unstructured) map[string]string {
+	labels, found, _ := unstructured.NestedStringMap(obj.Object, crossplaneSpecField(obj, "compositionSelector", "matchLabels")...)
+	if !found {
+		return nil
+	}
+	return labels
+}
+
+// crossplaneSpecField returns the path of a Crossplane machinery field of a composite resource or claim,
+// which Crossplane v2 XRs nest under spec.crossplane
+func crossplaneSpecField(obj *unstructured.Unstructured, fields ...string) []string {
+	path := []string{"spec"}
+	if _, found, _ := unstructured.NestedMap(obj.Object, "spec", "crossplane"); found {
+		path = append(path, "crossplane")
+	}
+	return append(path, fields...)
+}
@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+// ProviderDetail represents a Provider with the runtime of its active revision
+type ProviderDetail struct {
+	Provider
+	Runtime PackageRuntime `json:"runtime"`
+}
+
+// FunctionDetail represents a Function with the runtime of its active revision
+type FunctionDetail struct {
+	Function
+	Runtime PackageRuntime `json:"runtime"`
+}
+
+// PackageRuntime represents how a Provider or Function package runs:
+// its DeploymentRuntimeConfig, the Deployment created from it and the Deployment pods
+type PackageRuntime struct {
+	RuntimeConfig RuntimeConfigRef  `json:"runtimeConfig"`
+	Deployment    *DeploymentStatus `json:"deployment,omitempty"`
+	Pods          []PodStatus       `json:"pods"`
+}
+
+// RuntimeConfigRef represents the DeploymentRuntimeConfig of a package
+// Found is false when the referenced config does not exist, which prevents the package from running
+type RuntimeConfigRef struct {
+	Name  string                 `json:"name"`
+	Found bool                   `json:"found"`
+	Spec  map[string]interface{} `json:"spec,omitempty"`
+}
+
+// DeploymentStatus represents the state of the Deployment running a package
+type DeploymentStatus struct {
+	Name              string      `json:"name"`
+	Namespace         string      `json:"namespace"`
+	Image             string      `json:"image"`
+	Replicas          int32       `json:"replicas"`
+	ReadyReplicas     int32       `json:"readyReplicas"`
+	AvailableReplicas int32       `json:"availableReplicas"`
+	UpdatedReplicas   int32       `json:"updatedReplicas"`
+	Conditions        []Condition `json:"conditions,omitempty"`
+}
+
+// PodStatus represents the state of a package pod
+// Waiting is the reason the main container is not running (e.g. CrashLoopBackOff, ImagePullBackOff),
+// LastTermination the reason of its previous termination (e.g. OOMKilled, Error)
+type PodStatus struct {
+	Name                   string     `json:"name"`
+	Phase                  string     `json:"phase"`
+	Ready                  bool       `json:"ready"`
+	Image                  string     `json:"image"`
+	Restarts               int32      `json:"restarts"`
+	Waiting                string     `json:"waiting,omitempty"`
+	WaitingMessage         string     `json:"waitingMessage,omitempty"`
+	LastTermination        string     `json:"lastTermination,omitempty"`
+	LastTerminationMessage string     `json:"lastTerminationMessage,omitempty"`
+	LastTerminationTime    *time.Time `json:"lastTerminationTime,omitempty"`
+	Node                   string     `json:"node,omitempty"`
+}
+
+// LogLine is a line of the logs of a package pod, sent as a server-sent event
+type LogLine struct {
+	Pod       string `json:"pod"`
+	Container string `json:"container"`
+	Line      string `json:"line"`
+}
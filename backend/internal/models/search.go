@@ -0,0 +1,18 @@
+package models
+
+// SearchHit represents a Crossplane object matching a search query
+type SearchHit struct {
+	Kind       string        `json:"kind"`
+	APIVersion string        `json:"apiVersion"`
+	Category   string        `json:"category"`
+	Name       string        `json:"name"`
+	Namespace  string        `json:"namespace,omitempty"`
+	Score      int           `json:"score"`
+	Matches    []SearchMatch `json:"matches"`
+}
+
+// SearchMatch represents a field of an object matching a search term
+type SearchMatch struct {
+	Field   string `json:"field"`
+	Snippet string `json:"snippet"`
+}
@@ -0,0 +1,31 @@
+package models
+
+// How the Composition of a composite resource or claim is chosen, in the order Crossplane applies them
+const (
+	// SelectionEnforced is the enforcedCompositionRef of the XRD, which overrides any choice of the resource
+	SelectionEnforced = "Enforced"
+	// SelectionReference is an explicit compositionRef
+	SelectionReference = "Reference"
+	// SelectionSelector is a compositionSelector, Crossplane sets compositionRef to one of its matches
+	SelectionSelector = "Selector"
+	// SelectionDefault is the defaultCompositionRef of the XRD
+	SelectionDefault = "Default"
+	// SelectionNone is set when nothing selects a Composition
+	SelectionNone = "None"
+)
+
+// CompositionSelection explains how the Composition of a composite resource or claim was chosen
+type CompositionSelection struct {
+	Method string `json:"method"`
+	// Composition is the selected Composition, empty until Crossplane sets compositionRef
+	Composition string            `json:"composition,omitempty"`
+	Selector    map[string]string `json:"selector,omitempty"`
+	// Candidates are the Compositions of the composite type matching the selector
+	Candidates []string `json:"candidates,omitempty"`
+	// Default and Enforced are the Composition references of the XRD
+	Default  string `json:"default,omitempty"`
+	Enforced string `json:"enforced,omitempty"`
+	// CompositeType is the apiVersion and kind the Composition must compose
+	CompositeType TypeReference `json:"compositeType"`
+	Warnings      []string      `json:"warnings"`
+}
@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+// Snapshot triggers
+const (
+	SnapshotTriggerManual    = "manual"
+	SnapshotTriggerScheduled = "scheduled"
+	SnapshotTriggerLive      = "live"
+)
+
+// Snapshot represents the state of all Crossplane objects at a point in time
+// Objects are omitted when listing snapshots
+type Snapshot struct {
+	ID        string           `json:"id"`
+	CreatedAt time.Time        `json:"createdAt"`
+	Trigger   string           `json:"trigger"`
+	Count     int              `json:"count"`
+	Objects   []SnapshotObject `json:"objects,omitempty"`
+}
+
+// SnapshotObject represents an object captured in a snapshot
+type SnapshotObject struct {
+	BaseResource
+	Category string                 `json:"category"`
+	Status   ResourceStatus         `json:"status"`
+	Spec     map[string]interface{} `json:"spec,omitempty"`
+}
+
+// SnapshotObjectRef identifies an object across snapshots
+type SnapshotObjectRef struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+// SnapshotDiff represents the differences between two snapshots
+type SnapshotDiff struct {
+	From    string              `json:"from"`
+	To      string              `json:"to"`
+	Added   []SnapshotObjectRef `json:"added"`
+	Removed []SnapshotObjectRef `json:"removed"`
+	Changed []ObjectChange      `json:"changed"`
+}
+
+// ObjectChange represents the changes of an object present in both snapshots
+type ObjectChange struct {
+	SnapshotObjectRef
+	SpecChanges      []FieldChange     `json:"specChanges,omitempty"`
+	ConditionChanges []ConditionChange `json:"conditionChanges,omitempty"`
+}
+
+// FieldChange represents a changed spec field, Before or After is nil when the field was added or removed
+type FieldChange struct {
+	Path   string      `json:"path"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// ConditionChange represents a changed condition, Before or After is nil when the condition appeared or disappeared
+type ConditionChange struct {
+	Type   string     `json:"type"`
+	Before *Condition `json:"before"`
+	After  *Condition `json:"after"`
+}
@@ -0,0 +1,101 @@
+package models
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Usage represents a Usage or ClusterUsage, which blocks the deletion of the resource it is of
+// while the resource it is by exists
+type Usage struct {
+	BaseResource
+	Status UsageStatus `json:"status"`
+	Spec   UsageSpec   `json:"spec"`
+}
+
+// GetResourceStatus returns the common status fields
+func (u Usage) GetResourceStatus() ResourceStatus {
+	return u.Status.ResourceStatus
+}
+
+type UsageSpec struct {
+	Of UsageResource `json:"of"`
+	// By is optional since Crossplane v1.15, a Usage with a reason alone blocks the deletion unconditionally
+	By             *UsageResource `json:"by,omitempty"`
+	Reason         string         `json:"reason,omitempty"`
+	ReplayDeletion bool           `json:"replayDeletion,omitempty"`
+}
+
+type UsageStatus struct {
+	ResourceStatus
+}
+
+// UsageResource is a resource referenced by a Usage
+// Name is empty while Crossplane has not resolved the label selector into a resource reference
+type UsageResource struct {
+	ObjectRef
+	Selector map[string]string `json:"selector,omitempty"`
+	// Resolution against the current objects, set by the API handlers
+	Found    bool `json:"found"`
+	Ready    bool `json:"ready"`
+	Deleting bool `json:"deleting"`
+}
+
+// Resolved reports whether the reference names a resource
+func (r UsageResource) Resolved() bool {
+	return r.Name != ""
+}
+
+// SameObject reports whether two references point to the same object, regardless of their API version
+func (r ObjectRef) SameObject(o ObjectRef) bool {
+	return r.Name == o.Name && r.Namespace == o.Namespace && r.Kind == o.Kind &&
+		schema.FromAPIVersionAndKind(r.APIVersion, r.Kind).Group == schema.FromAPIVersionAndKind(o.APIVersion, o.Kind).Group
+}
+
+// ConvertToUsage converts a Usage or ClusterUsage
+// Resources referenced by a namespaced Usage are in its namespace unless the reference has its own
+func ConvertToUsage(obj *unstructured.Unstructured) Usage {
+	scope := ScopeCluster
+	if obj.GetNamespace() != "" {
+		scope = ScopeNamespace
+	}
+
+	usage := Usage{
+		BaseResource: ConvertToBaseResource(obj, scope),
+		Status:       UsageStatus{ResourceStatus: ConvertToResourceStatus(obj)},
+		Spec: UsageSpec{
+			Of: convertUsageResource(obj, "of"),
+		},
+	}
+	if _, found, _ := unstructured.NestedMap(obj.Object, "spec", "by"); found {
+		by := convertUsageResource(obj, "by")
+		usage.Spec.By = &by
+	}
+	usage.Spec.Reason, _, _ = unstructured.NestedString(obj.Object, "spec", "reason")
+	usage.Spec.ReplayDeletion, _, _ = unstructured.NestedBool(obj.Object, "spec", "replayDeletion")
+
+	return usage
+}
+
+// convertUsageResource extracts the spec.of or spec.by reference of a Usage
+func convertUsageResource(obj *unstructured.Unstructured, field string) UsageResource {
+	var r UsageResource
+	r.APIVersion, _, _ = unstructured.NestedString(obj.Object, "spec", field, "apiVersion")
+	r.Kind, _, _ = unstructured.NestedString(obj.Object, "spec", field, "kind")
+	r.Name, _, _ = unstructured.NestedString(obj.Object, "spec", field, "resourceRef", "name")
+	r.Selector, _, _ = unstructured.NestedStringMap(obj.Object, "spec", field, "resourceSelector", "matchLabels")
+
+	r.Namespace, _, _ = unstructured.NestedString(obj.Object, "spec", field, "resourceRef", "namespace")
+	if r.Namespace == "" {
+		r.Namespace = obj.GetNamespace()
+	}
+	return r
+}
+
+// Protection lists the Usages involving an object
+type Protection struct {
+	// ProtectedBy are the Usages blocking the deletion of the object
+	ProtectedBy []Usage `json:"protectedBy"`
+	// Protects are the Usages the object is the user of, blocking the deletion of other resources
+	Protects []Usage `json:"protects"`
+}
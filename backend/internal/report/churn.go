@@ -0,0 +1,100 @@
+package report
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gravitek/crossplane-spy/internal/history"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
+)
+
+// DefaultChurnWindow is how long spec updates are remembered by the churn tracker
+const DefaultChurnWindow = time.Hour
+
+// ChurnTracker counts the spec updates (generation changes) of the objects observed by the object cache
+// It is registered as an event handler on the object cache informers
+type ChurnTracker struct {
+	window time.Duration
+	now    func() time.Time
+
+	mu      sync.Mutex
+	updates map[history.ObjectKey][]time.Time
+}
+
+var _ cache.ResourceEventHandler = &ChurnTracker{}
+
+// NewChurnTracker creates a tracker remembering the updates of the given window
+func NewChurnTracker(window time.Duration) *ChurnTracker {
+	return &ChurnTracker{
+		window:  window,
+		now:     time.Now,
+		updates: make(map[history.ObjectKey][]time.Time),
+	}
+}
+
+// Window returns how long updates are remembered
+func (t *ChurnTracker) Window() time.Duration {
+	return t.window
+}
+
+// OnAdd does nothing, a new object has no update yet
+func (t *ChurnTracker) OnAdd(obj interface{}, isInInitialList bool) {}
+
+// OnUpdate records an update when the generation of the object changed
+// Status-only updates keep the generation unchanged and are ignored
+func (t *ChurnTracker) OnUpdate(oldObj, newObj interface{}) {
+	oldU, ok := oldObj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+	newU, ok := newObj.(*unstructured.Unstructured)
+	if !ok || newU.GetGeneration() == oldU.GetGeneration() {
+		return
+	}
+
+	key := history.KeyForObject(newU)
+	now := t.now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.updates[key] = append(t.prune(t.updates[key], now), now)
+}
+
+// OnDelete forgets the updates of the object
+func (t *ChurnTracker) OnDelete(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.updates, history.KeyForObject(u))
+}
+
+// Updates returns the times of the spec updates of an object within the window, oldest first
+func (t *ChurnTracker) Updates(key history.ObjectKey) []time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	updates := t.prune(t.updates[key], t.now())
+	if len(updates) == 0 {
+		delete(t.updates, key)
+		return nil
+	}
+	t.updates[key] = updates
+	return append([]time.Time(nil), updates...)
+}
+
+// prune drops the updates older than the window
+func (t *ChurnTracker) prune(updates []time.Time, now time.Time) []time.Time {
+	cutoff := now.Add(-t.window)
+	for len(updates) > 0 && updates[0].Before(cutoff) {
+		updates = updates[1:]
+	}
+	return updates
+}
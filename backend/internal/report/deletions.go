@@ -0,0 +1,115 @@
+package report
+
+import (
+	"sort"
+	"time"
+
+	"github.com/gravitek/crossplane-spy/internal/k8s"
+	"github.com/gravitek/crossplane-spy/internal/models"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// StuckDeletionOptions filters the stuck deletion report
+type StuckDeletionOptions struct {
+	// MinAge skips the objects terminating for less than this duration
+	MinAge time.Duration
+	// Category restricts the report to one object cache category, empty means all
+	Category string
+}
+
+// StuckDeletions lists the objects with a deletion timestamp, their remaining finalizers,
+// and the Usages protecting them or the resources they compose
+func StuckDeletions(objects []k8s.CachedObject, usages UsageIndex, opts StuckDeletionOptions) models.StuckDeletionReport {
+	now := time.Now()
+	report := models.StuckDeletionReport{
+		Kind:      "StuckDeletionReport",
+		Resources: []models.TerminatingResource{},
+	}
+
+	for _, cached := range objects {
+		obj := cached.Object
+		deletion := obj.GetDeletionTimestamp()
+		if deletion == nil || (opts.Category != "" && cached.Category != opts.Category) {
+			continue
+		}
+		terminating := now.Sub(deletion.Time)
+		if terminating < opts.MinAge {
+			continue
+		}
+
+		ref := objectRef(obj)
+		blocking := blockingUsages(usages, ref)
+		for _, composed := range composedRefs(obj) {
+			blocking = append(blocking, blockingUsages(usages, composed)...)
+		}
+
+		finalizers := obj.GetFinalizers()
+		if finalizers == nil {
+			finalizers = []string{}
+		}
+		report.Resources = append(report.Resources, models.TerminatingResource{
+			ObjectRef:          ref,
+			Category:           cached.Category,
+			DeletionTimestamp:  deletion.Time,
+			TerminatingSeconds: int64(terminating.Seconds()),
+			Finalizers:         finalizers,
+			Conditions:         models.ConvertToResourceStatus(obj).Conditions,
+			BlockingUsages:     blocking,
+		})
+	}
+
+	sort.Slice(report.Resources, func(i, j int) bool {
+		return report.Resources[i].DeletionTimestamp.Before(report.Resources[j].DeletionTimestamp)
+	})
+
+	report.Count = len(report.Resources)
+	report.GeneratedAt = now
+	return report
+}
+
+// blockingUsages returns the Usages protecting the referenced object
+func blockingUsages(usages UsageIndex, of models.ObjectRef) []models.BlockingUsage {
+	blocking := []models.BlockingUsage{}
+	for _, usage := range usages.Protecting(of) {
+		b := models.BlockingUsage{
+			ObjectRef: models.ObjectRef{APIVersion: usage.APIVersion, Kind: usage.Kind, Namespace: usage.Metadata.Namespace, Name: usage.Metadata.Name},
+			Of:        of,
+			Reason:    usage.Spec.Reason,
+		}
+		if usage.Spec.By != nil {
+			b.By = &usage.Spec.By.ObjectRef
+		}
+		blocking = append(blocking, b)
+	}
+	return blocking
+}
+
+// composedRefs returns the resources composed by a composite resource,
+// from spec.resourceRefs (Crossplane v1) or spec.crossplane.resourceRefs (v2)
+// Composed resources of a namespaced XR are in its namespace
+func composedRefs(obj *unstructured.Unstructured) []models.ObjectRef {
+	refs, found, _ := unstructured.NestedSlice(obj.Object, "spec", "resourceRefs")
+	if !found {
+		refs, _, _ = unstructured.NestedSlice(obj.Object, "spec", "crossplane", "resourceRefs")
+	}
+
+	var composed []models.ObjectRef
+	for _, r := range refs {
+		ref, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		apiVersion, _ := ref["apiVersion"].(string)
+		kind, _ := ref["kind"].(string)
+		name, _ := ref["name"].(string)
+		if name == "" {
+			continue
+		}
+		namespace, _ := ref["namespace"].(string)
+		if namespace == "" {
+			namespace = obj.GetNamespace()
+		}
+		composed = append(composed, models.ObjectRef{APIVersion: apiVersion, Kind: kind, Namespace: namespace, Name: name})
+	}
+	return composed
+}
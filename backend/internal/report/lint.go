@@ -0,0 +1,387 @@
+package report
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gravitek/crossplane-spy/internal/k8s"
+	"github.com/gravitek/crossplane-spy/internal/models"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Rules checked by the composition lint
+const (
+	// RuleMissingFunction flags pipeline steps whose Function is not installed
+	RuleMissingFunction = "MissingFunction"
+	// RuleDuplicateStep flags pipeline steps sharing a name, Crossplane rejects the Composition
+	RuleDuplicateStep = "DuplicateStep"
+	// RuleUnknownCompositeType flags a compositeTypeRef defined by no XRD
+	RuleUnknownCompositeType = "UnknownCompositeType"
+	// RuleXRDNotEstablished flags a compositeTypeRef whose XRD is not Established
+	RuleXRDNotEstablished = "XRDNotEstablished"
+	// RuleUnservedVersion flags a compositeTypeRef version the XRD does not serve
+	RuleUnservedVersion = "UnservedVersion"
+	// RuleLegacyResourcesMode flags mode: Resources, removed in Crossplane v2
+	RuleLegacyResourcesMode = "LegacyResourcesMode"
+	// RuleUnknownPatchField flags patches reading or writing a composite resource field absent from the XRD schema
+	RuleUnknownPatchField = "UnknownPatchField"
+)
+
+// Composition modes, Resources is the default before Crossplane v2 and Pipeline since
+const (
+	modeResources = "Resources"
+	modePipeline  = "Pipeline"
+)
+
+// patchAndTransformGroup is the API group of the input of function-patch-and-transform
+const patchAndTransformGroup = "pt.fn.crossplane.io"
+
+// crossplaneFields are the composite resource fields Crossplane adds to the XRD schema
+var crossplaneFields = map[string][]string{
+	"spec": {"compositionRef", "compositionSelector", "compositionRevisionRef", "compositionRevisionSelector",
+		"compositionUpdatePolicy", "claimRef", "resourceRefs", "writeConnectionSecretToRef", "publishConnectionDetailsTo",
+		"environmentConfigRefs", "crossplane"},
+	"status": {"conditions", "connectionDetails", "claimConditionTypes", "crossplane"},
+}
+
+// LintComposition runs static checks against a Composition: its pipeline Functions and step names,
+// the XRD of its compositeTypeRef, legacy Resources mode on Crossplane v2 and the composite resource
+// fields its patches use
+func LintComposition(composition *unstructured.Unstructured, xrds, functions []k8s.CachedObject, crossplaneV2 bool) models.CompositionLintReport {
+	report := models.CompositionLintReport{
+		Kind:         "CompositionLintReport",
+		Composition:  composition.GetName(),
+		CrossplaneV2: crossplaneV2,
+		Findings:     []models.LintFinding{},
+	}
+	report.CompositeType.APIVersion, _, _ = unstructured.NestedString(composition.Object, "spec", "compositeTypeRef", "apiVersion")
+	report.CompositeType.Kind, _, _ = unstructured.NestedString(composition.Object, "spec", "compositeTypeRef", "kind")
+
+	report.Mode, _, _ = unstructured.NestedString(composition.Object, "spec", "mode")
+	modePath := "spec.mode"
+	if report.Mode == "" {
+		report.Mode = modeResources
+		if crossplaneV2 {
+			report.Mode = modePipeline
+		}
+		modePath = "spec.resources"
+	}
+
+	xrSchema, findings := lintCompositeType(report.CompositeType, xrds)
+	report.Findings = append(report.Findings, findings...)
+	report.Findings = append(report.Findings, lintPipeline(composition, functions)...)
+
+	_, hasResources, _ := unstructured.NestedSlice(composition.Object, "spec", "resources")
+	if crossplaneV2 && (report.Mode == modeResources || hasResources) {
+		report.Findings = append(report.Findings, models.LintFinding{
+			Rule:     RuleLegacyResourcesMode,
+			Severity: models.LintError,
+			Path:     modePath,
+			Message:  "Crossplane v2 removed mode: Resources, move the resources to a function-patch-and-transform pipeline step",
+		})
+	}
+
+	if xrSchema != nil {
+		report.Findings = append(report.Findings, lintPatches(composition, xrSchema)...)
+	}
+
+	for _, finding := range report.Findings {
+		switch finding.Severity {
+		case models.LintError:
+			report.Errors++
+		case models.LintWarning:
+			report.Warnings++
+		}
+	}
+	report.GeneratedAt = time.Now()
+	return report
+}
+
+// lintCompositeType checks that an Established XRD serves the composite type,
+// and returns the schema of the served version, nil when unknown
+func lintCompositeType(typ models.TypeReference, xrds []k8s.CachedObject) (map[string]interface{}, []models.LintFinding) {
+	gv, err := schema.ParseGroupVersion(typ.APIVersion)
+	if err != nil || typ.Kind == "" {
+		return nil, []models.LintFinding{{
+			Rule:     RuleUnknownCompositeType,
+			Severity: models.LintError,
+			Path:     "spec.compositeTypeRef",
+			Message:  fmt.Sprintf("Invalid compositeTypeRef %s %s", typ.APIVersion, typ.Kind),
+		}}
+	}
+
+	var xrd *unstructured.Unstructured
+	for _, cached := range xrds {
+		group, _, _ := unstructured.NestedString(cached.Object.Object, "spec", "group")
+		kind, _, _ := unstructured.NestedString(cached.Object.Object, "spec", "names", "kind")
+		if group == gv.Group && kind == typ.Kind {
+			xrd = cached.Object
+			break
+		}
+	}
+	if xrd == nil {
+		return nil, []models.LintFinding{{
+			Rule:     RuleUnknownCompositeType,
+			Severity: models.LintError,
+			Path:     "spec.compositeTypeRef",
+			Message:  fmt.Sprintf("No XRD defines %s in group %s", typ.Kind, gv.Group),
+		}}
+	}
+
+	var findings []models.LintFinding
+	status, _, _ := unstructured.NestedMap(xrd.Object, "status")
+	if !models.IsXRDEstablished(models.ConvertConditions(status)) {
+		findings = append(findings, models.LintFinding{
+			Rule:     RuleXRDNotEstablished,
+			Severity: models.LintError,
+			Path:     "spec.compositeTypeRef",
+			Message:  fmt.Sprintf("XRD %s is not Established", xrd.GetName()),
+		})
+	}
+
+	versions, _, _ := unstructured.NestedSlice(xrd.Object, "spec", "versions")
+	for _, v := range versions {
+		m, ok := v.(map[string]interface{})
+		if !ok || m["name"] != gv.Version {
+			continue
+		}
+		if served, _ := m["served"].(bool); !served {
+			return nil, append(findings, models.LintFinding{
+				Rule:     RuleUnservedVersion,
+				Severity: models.LintError,
+				Path:     "spec.compositeTypeRef.apiVersion",
+				Message:  fmt.Sprintf("XRD %s does not serve version %s", xrd.GetName(), gv.Version),
+			})
+		}
+		xrSchema, _, _ := unstructured.NestedMap(m, "schema", "openAPIV3Schema")
+		return xrSchema, findings
+	}
+	return nil, append(findings, models.LintFinding{
+		Rule:     RuleUnservedVersion,
+		Severity: models.LintError,
+		Path:     "spec.compositeTypeRef.apiVersion",
+		Message:  fmt.Sprintf("XRD %s does not define version %s", xrd.GetName(), gv.Version),
+	})
+}
+
+// lintPipeline checks that pipeline step names are unique and that their Functions are installed
+func lintPipeline(composition *unstructured.Unstructured, functions []k8s.CachedObject) []models.LintFinding {
+	installed := make(map[string]bool, len(functions))
+	for _, cached := range functions {
+		installed[cached.Object.GetName()] = true
+	}
+
+	var findings []models.LintFinding
+	steps, _, _ := unstructured.NestedSlice(composition.Object, "spec", "pipeline")
+	seen := make(map[string]int, len(steps))
+	for i, s := range steps {
+		step, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		path := fmt.Sprintf("spec.pipeline[%d]", i)
+
+		name, _ := step["step"].(string)
+		if first, ok := seen[name]; ok {
+			findings = append(findings, models.LintFinding{
+				Rule:     RuleDuplicateStep,
+				Severity: models.LintError,
+				Path:     path + ".step",
+				Message:  fmt.Sprintf("Step %q is already defined by spec.pipeline[%d]", name, first),
+			})
+		} else {
+			seen[name] = i
+		}
+
+		function, _, _ := unstructured.NestedString(step, "functionRef", "name")
+		if !installed[function] {
+			findings = append(findings, models.LintFinding{
+				Rule:     RuleMissingFunction,
+				Severity: models.LintError,
+				Path:     path + ".functionRef.name",
+				Message:  fmt.Sprintf("Function %q of step %q is not installed", function, name),
+			})
+		}
+	}
+	return findings
+}
+
+// lintPatches checks the composite resource fields read and written by the patches of the legacy resources
+// and of the function-patch-and-transform steps, including their patch sets
+func lintPatches(composition *unstructured.Unstructured, xrSchema map[string]interface{}) []models.LintFinding {
+	spec, _, _ := unstructured.NestedMap(composition.Object, "spec")
+	patches := resourcePatches(spec, "spec")
+
+	steps, _, _ := unstructured.NestedSlice(composition.Object, "spec", "pipeline")
+	for i, s := range steps {
+		input, _, _ := unstructured.NestedMap(asMap(s), "input")
+		apiVersion, _ := input["apiVersion"].(string)
+		if schema.FromAPIVersionAndKind(apiVersion, "").Group == patchAndTransformGroup {
+			patches = append(patches, resourcePatches(input, fmt.Sprintf("spec.pipeline[%d].input", i))...)
+		}
+	}
+
+	var findings []models.LintFinding
+	for _, p := range patches {
+		for _, field := range compositeFields(p.patch) {
+			if known, reason := compositeFieldKnown(xrSchema, field.value); !known {
+				findings = append(findings, models.LintFinding{
+					Rule:     RuleUnknownPatchField,
+					Severity: models.LintWarning,
+					Path:     p.path + "." + field.name,
+					Message:  fmt.Sprintf("Composite resource field %q %s", field.value, reason),
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// locatedPatch is a patch with its path in the Composition
+type locatedPatch struct {
+	path  string
+	patch map[string]interface{}
+}
+
+// resourcePatches returns the patches of the resources and patch sets of a Composition spec or patch-and-transform input
+func resourcePatches(container map[string]interface{}, path string) []locatedPatch {
+	var patches []locatedPatch
+	for _, list := range []string{"resources", "patchSets"} {
+		items, _, _ := unstructured.NestedSlice(container, list)
+		for i, item := range items {
+			itemPatches, _, _ := unstructured.NestedSlice(asMap(item), "patches")
+			for j, patch := range itemPatches {
+				if m, ok := patch.(map[string]interface{}); ok {
+					patches = append(patches, locatedPatch{path: fmt.Sprintf("%s.%s[%d].patches[%d]", path, list, i, j), patch: m})
+				}
+			}
+		}
+	}
+	return patches
+}
+
+// patchField is a composite resource field path of a patch, with its field name relative to the patch
+type patchField struct {
+	name  string
+	value string
+}
+
+// compositeFields returns the composite resource field paths of a patch, depending on its type
+func compositeFields(patch map[string]interface{}) []patchField {
+	typ, _ := patch["type"].(string)
+	field := func(name string) []patchField {
+		if value, _ := patch[name].(string); value != "" {
+			return []patchField{{name: name, value: value}}
+		}
+		return nil
+	}
+
+	switch typ {
+	case "", "FromCompositeFieldPath":
+		return field("fromFieldPath")
+	case "ToCompositeFieldPath", "CombineToComposite":
+		return field("toFieldPath")
+	case "CombineFromComposite":
+		var fields []patchField
+		variables, _, _ := unstructured.NestedSlice(patch, "combine", "variables")
+		for i, v := range variables {
+			if value, _ := asMap(v)["fromFieldPath"].(string); value != "" {
+				fields = append(fields, patchField{name: fmt.Sprintf("combine.variables[%d].fromFieldPath", i), value: value})
+			}
+		}
+		return fields
+	}
+	return nil
+}
+
+// compositeFieldKnown reports whether a composite resource field path is defined by the XRD schema,
+// the Crossplane machinery fields or the object metadata, with the reason when it is not
+func compositeFieldKnown(xrSchema map[string]interface{}, path string) (bool, string) {
+	segments, err := fieldPathSegments(path)
+	if err != nil {
+		return false, fmt.Sprintf("is invalid: %v", err)
+	}
+
+	switch segments[0].name {
+	case "apiVersion", "kind", "metadata":
+		return true, ""
+	case "spec", "status":
+		if len(segments) > 1 && slices.Contains(crossplaneFields[segments[0].name], segments[1].name) {
+			return true, ""
+		}
+	default:
+		return false, "is not under spec, status or metadata"
+	}
+
+	for _, segment := range segments {
+		if preserve, _ := xrSchema["x-kubernetes-preserve-unknown-fields"].(bool); preserve {
+			return true, ""
+		}
+
+		var next interface{}
+		if segment.index {
+			next = xrSchema["items"]
+		} else if properties, ok := xrSchema["properties"].(map[string]interface{}); ok && properties[segment.name] != nil {
+			next = properties[segment.name]
+		} else if additional, ok := xrSchema["additionalProperties"].(bool); ok {
+			if additional {
+				return true, ""
+			}
+		} else {
+			next = xrSchema["additionalProperties"]
+		}
+
+		m, ok := next.(map[string]interface{})
+		if !ok {
+			return false, "is not defined by the XRD schema"
+		}
+		xrSchema = m
+	}
+	return true, ""
+}
+
+// fieldSegment is a field name or array index of a field path
+type fieldSegment struct {
+	name  string
+	index bool
+}
+
+// fieldPathSegments splits a Crossplane field path such as spec.subnets[0].id or metadata.labels[example.org/team]
+func fieldPathSegments(path string) ([]fieldSegment, error) {
+	var segments []fieldSegment
+	for rest := path; rest != ""; {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated [")
+			}
+			key := rest[1:end]
+			_, err := strconv.Atoi(key)
+			segments = append(segments, fieldSegment{name: key, index: err == nil})
+			rest = rest[end+1:]
+		default:
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			segments = append(segments, fieldSegment{name: rest[:end]})
+			rest = rest[end:]
+		}
+	}
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("empty field path")
+	}
+	return segments, nil
+}
+
+func asMap(v interface{}) map[string]interface{} {
+	m, _ := v.(map[string]interface{})
+	return m
+}
@@ -0,0 +1,160 @@
+package report
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/gravitek/crossplane-spy/internal/k8s"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestLintComposition(t *testing.T) {
+	xrd := func(version map[string]interface{}, established string) k8s.CachedObject {
+		return k8s.CachedObject{Object: &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apiextensions.crossplane.io/v1", "kind": "CompositeResourceDefinition",
+			"metadata": map[string]interface{}{"name": "xnetworks.example.org"},
+			"spec": map[string]interface{}{
+				"group":    "example.org",
+				"names":    map[string]interface{}{"kind": "XNetwork", "plural": "xnetworks"},
+				"versions": []interface{}{version},
+			},
+			"status": map[string]interface{}{"conditions": []interface{}{
+				map[string]interface{}{"type": "Established", "status": established},
+			}},
+		}}}
+	}
+	xrSchema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"spec": map[string]interface{}{"type": "object", "properties": map[string]interface{}{
+				"region":  map[string]interface{}{"type": "string"},
+				"subnets": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "object", "properties": map[string]interface{}{"cidr": map[string]interface{}{"type": "string"}}}},
+				"tags":    map[string]interface{}{"type": "object", "additionalProperties": map[string]interface{}{"type": "string"}},
+				"extra":   map[string]interface{}{"type": "object", "x-kubernetes-preserve-unknown-fields": true},
+			}},
+		},
+	}
+	served := xrd(map[string]interface{}{"name": "v1alpha1", "served": true, "schema": map[string]interface{}{"openAPIV3Schema": xrSchema}}, "True")
+	functions := []k8s.CachedObject{{Object: &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "pkg.crossplane.io/v1", "kind": "Function",
+		"metadata": map[string]interface{}{"name": "function-patch-and-transform"},
+	}}}}
+
+	composition := func(apiVersion string, spec map[string]interface{}) *unstructured.Unstructured {
+		spec["compositeTypeRef"] = map[string]interface{}{"apiVersion": apiVersion, "kind": "XNetwork"}
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apiextensions.crossplane.io/v1", "kind": "Composition",
+			"metadata": map[string]interface{}{"name": "xnetworks"},
+			"spec":     spec,
+		}}
+	}
+	step := func(name, function string, input map[string]interface{}) interface{} {
+		return map[string]interface{}{"step": name, "functionRef": map[string]interface{}{"name": function}, "input": input}
+	}
+	patches := func(patches ...interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"apiVersion": "pt.fn.crossplane.io/v1beta1", "kind": "Resources",
+			"resources": []interface{}{map[string]interface{}{"name": "vpc", "patches": patches}},
+		}
+	}
+	from := func(path string) interface{} {
+		return map[string]interface{}{"fromFieldPath": path, "toFieldPath": "spec.forProvider.unknown"}
+	}
+
+	for _, tc := range []struct {
+		name         string
+		composition  *unstructured.Unstructured
+		xrds         []k8s.CachedObject
+		crossplaneV2 bool
+		want         []string
+	}{
+		{
+			name: "valid pipeline",
+			composition: composition("example.org/v1alpha1", map[string]interface{}{"mode": "Pipeline", "pipeline": []interface{}{
+				step("patch", "function-patch-and-transform", patches(from("spec.region"), from("spec.subnets[0].cidr"),
+					from("spec.tags[example.org/team]"), from("spec.extra.anything"), from("metadata.labels[team]"),
+					from("spec.claimRef.namespace"), map[string]interface{}{"type": "PatchSet", "patchSetName": "common"})),
+			}}),
+			xrds: []k8s.CachedObject{served}, crossplaneV2: true,
+		},
+		{
+			name: "missing function and duplicate step",
+			composition: composition("example.org/v1alpha1", map[string]interface{}{"mode": "Pipeline", "pipeline": []interface{}{
+				step("patch", "function-patch-and-transform", nil),
+				step("patch", "function-go-templating", nil),
+			}}),
+			xrds: []k8s.CachedObject{served},
+			want: []string{RuleDuplicateStep + " spec.pipeline[1].step", RuleMissingFunction + " spec.pipeline[1].functionRef.name"},
+		},
+		{
+			name:        "unknown composite type",
+			composition: composition("example.org/v1alpha1", map[string]interface{}{"mode": "Pipeline"}),
+			want:        []string{RuleUnknownCompositeType + " spec.compositeTypeRef"},
+		},
+		{
+			name:        "XRD not established and version not served",
+			composition: composition("example.org/v1alpha1", map[string]interface{}{"mode": "Pipeline"}),
+			xrds:        []k8s.CachedObject{xrd(map[string]interface{}{"name": "v1alpha1", "served": false}, "False")},
+			want:        []string{RuleXRDNotEstablished + " spec.compositeTypeRef", RuleUnservedVersion + " spec.compositeTypeRef.apiVersion"},
+		},
+		{
+			name:        "undefined version",
+			composition: composition("example.org/v1", map[string]interface{}{"mode": "Pipeline"}),
+			xrds:        []k8s.CachedObject{served},
+			want:        []string{RuleUnservedVersion + " spec.compositeTypeRef.apiVersion"},
+		},
+		{
+			name: "legacy resources before Crossplane v2",
+			composition: composition("example.org/v1alpha1", map[string]interface{}{
+				"resources": []interface{}{map[string]interface{}{"name": "vpc", "patches": []interface{}{from("spec.region")}}},
+			}),
+			xrds: []k8s.CachedObject{served},
+		},
+		{
+			name: "legacy resources on Crossplane v2",
+			composition: composition("example.org/v1alpha1", map[string]interface{}{
+				"resources": []interface{}{map[string]interface{}{"name": "vpc", "patches": []interface{}{from("spec.region")}}},
+			}),
+			xrds: []k8s.CachedObject{served}, crossplaneV2: true,
+			want: []string{RuleLegacyResourcesMode + " spec.resources"},
+		},
+		{
+			name: "unknown patch fields",
+			composition: composition("example.org/v1alpha1", map[string]interface{}{
+				"mode":      "Resources",
+				"patchSets": []interface{}{map[string]interface{}{"name": "common", "patches": []interface{}{from("spec.zone")}}},
+				"resources": []interface{}{map[string]interface{}{"name": "vpc", "patches": []interface{}{
+					map[string]interface{}{"type": "ToCompositeFieldPath", "fromFieldPath": "status.atProvider.id", "toFieldPath": "status.vpcId"},
+					map[string]interface{}{"type": "CombineFromComposite", "combine": map[string]interface{}{"variables": []interface{}{
+						map[string]interface{}{"fromFieldPath": "spec.region"},
+						map[string]interface{}{"fromFieldPath": "spec.subnets.cidr"},
+					}}},
+					from("spec.tags[team"),
+					from("data.key"),
+				}}},
+			}),
+			xrds: []k8s.CachedObject{served},
+			want: []string{
+				RuleUnknownPatchField + " spec.resources[0].patches[0].toFieldPath",
+				RuleUnknownPatchField + " spec.resources[0].patches[1].combine.variables[1].fromFieldPath",
+				RuleUnknownPatchField + " spec.resources[0].patches[2].fromFieldPath",
+				RuleUnknownPatchField + " spec.resources[0].patches[3].fromFieldPath",
+				RuleUnknownPatchField + " spec.patchSets[0].patches[0].fromFieldPath",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := LintComposition(tc.composition, tc.xrds, functions, tc.crossplaneV2)
+			var got []string
+			for _, f := range r.Findings {
+				got = append(got, f.Rule+" "+f.Path)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("got findings %v, want %v", got, tc.want)
+			}
+			if r.Errors+r.Warnings != len(r.Findings) {
+				t.Errorf("got %d errors and %d warnings for %d findings", r.Errors, r.Warnings, len(r.Findings))
+			}
+		})
+	}
+}
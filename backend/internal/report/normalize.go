@@ -0,0 +1,38 @@
+package report
+
+import (
+	"regexp"
+	"strings"
+)
+
+// messagePatterns replace the variable parts of error messages, most specific first,
+// so that the same error on different resources normalizes to the same message
+var messagePatterns = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`(?i)(request ?id:?\s*)[0-9a-z-]+`), "${1}<id>"},
+	{regexp.MustCompile(`arn:aws[a-z-]*:[^\s,"']+`), "<arn>"},
+	{regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`), "<uuid>"},
+	{regexp.MustCompile(`\b\d{1,3}(\.\d{1,3}){3}(:\d+)?\b`), "<ip>"},
+	{regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`), "<time>"},
+	{regexp.MustCompile(`"[^"]*"`), `"*"`},
+	{regexp.MustCompile(`'[^']*'`), `'*'`},
+	{regexp.MustCompile(`(?i)\b[0-9a-f]{8,}\b`), "<id>"},
+	{regexp.MustCompile(`\b\d+\b`), "<n>"},
+	{regexp.MustCompile(`\s+`), " "},
+}
+
+// NormalizeMessage replaces the names of the object and the variable parts of an error message
+// (IDs, ARNs, addresses, timestamps, quoted values and numbers) with placeholders
+func NormalizeMessage(message string, names ...string) string {
+	for _, name := range names {
+		if len(name) > 2 {
+			message = strings.ReplaceAll(message, name, "<name>")
+		}
+	}
+	for _, p := range messagePatterns {
+		message = p.pattern.ReplaceAllString(message, p.replacement)
+	}
+	return strings.TrimSpace(message)
+}
@@ -0,0 +1,35 @@
+package report
+
+import "testing"
+
+func TestNormalizeMessage(t *testing.T) {
+	tests := []struct {
+		message string
+		names   []string
+		want    string
+	}{
+		{
+			message: "observe failed: cannot get bucket logs: operation error S3: HeadBucket, https response error StatusCode: 403, RequestID: 8XK2P1Q9, api error Forbidden",
+			names:   []string{"logs"},
+			want:    "observe failed: cannot get bucket <name>: operation error S3: HeadBucket, https response error StatusCode: <n>, RequestID: <id>, api error Forbidden",
+		},
+		{
+			message: `create failed: role "arn:aws:iam::123456789012:role/xp" not found`,
+			want:    `create failed: role "*" not found`,
+		},
+		{
+			message: "cannot reach 10.0.12.7:443 since 2025-04-01T08:00:12Z for 3f2a9c1e-0b4d-4c8e-9a7f-1e2d3c4b5a69",
+			want:    "cannot reach <ip> since <time> for <uuid>",
+		},
+		{
+			message: "update failed:   ARN arn:aws:s3:::acme-logs-prod\n is locked",
+			want:    "update failed: ARN <arn> is locked",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeMessage(tt.message, tt.names...); got != tt.want {
+			t.Errorf("NormalizeMessage(%q)\ngot:  %q\nwant: %q", tt.message, got, tt.want)
+		}
+	}
+}
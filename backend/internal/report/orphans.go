@@ -0,0 +1,105 @@
+package report
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/gravitek/crossplane-spy/internal/k8s"
+	"github.com/gravitek/crossplane-spy/internal/models"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// compositeLabel is set by Crossplane on composed resources to the name of their composite resource
+const compositeLabel = "crossplane.io/composite"
+
+// ObjectLookup returns the referenced object, nil when it does not exist
+type ObjectLookup func(ref models.ObjectRef) *unstructured.Unstructured
+
+// Orphans finds the managed resources whose owner reference or crossplane.io/composite label points to a missing
+// composite resource, the managed resources with no owner at all, and the composite resources whose resourceRefs
+// point to missing objects
+// Owner references are matched by UID, so a composite resource recreated with the same name does not own them
+// The label only holds a name, so a managed resource without owner reference needs a composite resource of
+// that name listing it in its resourceRefs, whatever composite kinds share the name
+func Orphans(managed, composites []k8s.CachedObject, lookup ObjectLookup) models.OrphanReport {
+	report := models.OrphanReport{
+		Kind:        "OrphanReport",
+		Orphaned:    []models.OrphanedResource{},
+		Unowned:     []models.OrphanedResource{},
+		MissingRefs: []models.CompositeMissingRefs{},
+	}
+
+	// composedBy holds the resourceRefs of the composite resources by composite name
+	composedBy := make(map[string][]models.ObjectRef, len(composites))
+	for _, cached := range composites {
+		name := cached.Object.GetName()
+		composedBy[name] = append(composedBy[name], composedRefs(cached.Object)...)
+	}
+
+	for _, cached := range managed {
+		obj := cached.Object
+		report.ManagedCount++
+
+		orphan := models.OrphanedResource{
+			ObjectRef:         objectRef(obj),
+			Composite:         obj.GetLabels()[compositeLabel],
+			Ready:             models.ConvertToResourceStatus(obj).Ready,
+			CreationTimestamp: obj.GetCreationTimestamp().Time,
+		}
+
+		owners := obj.GetOwnerReferences()
+		switch {
+		case len(owners) > 0:
+			// Composed resources are controlled by their composite resource, other owners are checked otherwise
+			owner := owners[0]
+			for _, o := range owners {
+				if o.Controller != nil && *o.Controller {
+					owner = o
+					break
+				}
+			}
+			ref := models.ObjectRef{APIVersion: owner.APIVersion, Kind: owner.Kind, Namespace: obj.GetNamespace(), Name: owner.Name}
+			if found := lookup(ref); found == nil || found.GetUID() != owner.UID {
+				orphan.Owner = &ref
+				report.Orphaned = append(report.Orphaned, orphan)
+			}
+		case orphan.Composite != "":
+			if !slices.ContainsFunc(composedBy[orphan.Composite], orphan.ObjectRef.SameObject) {
+				report.Orphaned = append(report.Orphaned, orphan)
+			}
+		default:
+			report.Unowned = append(report.Unowned, orphan)
+		}
+	}
+
+	for _, cached := range composites {
+		var missing []models.ObjectRef
+		for _, ref := range composedRefs(cached.Object) {
+			if lookup(ref) == nil {
+				missing = append(missing, ref)
+			}
+		}
+		if len(missing) > 0 {
+			report.MissingRefs = append(report.MissingRefs, models.CompositeMissingRefs{ObjectRef: objectRef(cached.Object), Missing: missing})
+		}
+	}
+
+	byAge := func(resources []models.OrphanedResource) {
+		sort.Slice(resources, func(i, j int) bool {
+			return resources[i].CreationTimestamp.Before(resources[j].CreationTimestamp)
+		})
+	}
+	byAge(report.Orphaned)
+	byAge(report.Unowned)
+	sort.Slice(report.MissingRefs, func(i, j int) bool {
+		a, b := report.MissingRefs[i], report.MissingRefs[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+
+	report.GeneratedAt = time.Now()
+	return report
+}
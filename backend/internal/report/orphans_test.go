@@ -0,0 +1,46 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/gravitek/crossplane-spy/internal/k8s"
+	"github.com/gravitek/crossplane-spy/internal/models"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestOrphansByCompositeLabel(t *testing.T) {
+	bucket := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "s3.aws.upbound.io/v1beta1", "kind": "Bucket",
+		"metadata": map[string]interface{}{"name": "net-0-artifacts", "labels": map[string]interface{}{"crossplane.io/composite": "net-0"}},
+	}}
+	composite := func(kind string, refs ...string) k8s.CachedObject {
+		var resourceRefs []interface{}
+		for _, name := range refs {
+			resourceRefs = append(resourceRefs, map[string]interface{}{"apiVersion": "s3.aws.upbound.io/v1beta2", "kind": "Bucket", "name": name})
+		}
+		return k8s.CachedObject{Object: &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "example.org/v1alpha1", "kind": kind,
+			"metadata": map[string]interface{}{"name": "net-0"},
+			"spec":     map[string]interface{}{"resourceRefs": resourceRefs},
+		}}}
+	}
+	lookup := func(ref models.ObjectRef) *unstructured.Unstructured { return nil }
+
+	for _, tc := range []struct {
+		name       string
+		composites []k8s.CachedObject
+		orphaned   bool
+	}{
+		{name: "composite listing the resource", composites: []k8s.CachedObject{composite("XNetwork", "net-0-artifacts")}},
+		{name: "composites of several kinds with the same name", composites: []k8s.CachedObject{composite("XDatabase"), composite("XNetwork", "net-0-artifacts")}},
+		{name: "composite of another kind with the same name", composites: []k8s.CachedObject{composite("XDatabase", "net-0-db")}, orphaned: true},
+		{name: "missing composite", orphaned: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			report := Orphans([]k8s.CachedObject{{Object: bucket}}, tc.composites, lookup)
+			if orphaned := len(report.Orphaned) == 1; orphaned != tc.orphaned {
+				t.Errorf("got orphaned %v, want %v", orphaned, tc.orphaned)
+			}
+		})
+	}
+}
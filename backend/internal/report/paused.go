@@ -0,0 +1,113 @@
+package report
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/gravitek/crossplane-spy/internal/k8s"
+	"github.com/gravitek/crossplane-spy/internal/models"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// PausedOptions filters the paused report
+type PausedOptions struct {
+	// MinAge skips the objects paused for less than this duration, objects with an unknown pause time are kept
+	MinAge time.Duration
+	// Category restricts the report to one object cache category, empty means all
+	Category string
+}
+
+// Paused lists the objects with the crossplane.io/paused annotation or a ReconcilePaused Synced condition,
+// with the field manager that set the annotation and when
+func Paused(objects []k8s.CachedObject, opts PausedOptions) models.PausedReport {
+	now := time.Now()
+	report := models.PausedReport{
+		Kind:      "PausedReport",
+		Resources: []models.PausedResource{},
+	}
+
+	for _, cached := range objects {
+		obj := cached.Object
+		if opts.Category != "" && cached.Category != opts.Category {
+			continue
+		}
+		status := models.ConvertToResourceStatus(obj)
+		if !models.IsPaused(obj, status.Conditions) {
+			continue
+		}
+
+		paused := models.PausedResource{
+			ObjectRef: objectRef(obj),
+			Category:  cached.Category,
+			Annotated: obj.GetAnnotations()[models.PausedAnnotation] == "true",
+		}
+		paused.PausedBy, paused.Operation, paused.PausedAt = pausedBy(obj)
+		if synced, ok := findCondition(status.Conditions, "Synced"); ok && synced.Reason == models.ReasonReconcilePaused && !synced.LastTransitionTime.IsZero() {
+			since := synced.LastTransitionTime
+			paused.PausedSince = &since
+		}
+
+		if start := pauseStart(paused); start != nil && now.Sub(*start) < opts.MinAge {
+			continue
+		}
+		report.Resources = append(report.Resources, paused)
+	}
+
+	// Oldest first, objects with an unknown pause time last
+	sort.SliceStable(report.Resources, func(i, j int) bool {
+		a, b := pauseStart(report.Resources[i]), pauseStart(report.Resources[j])
+		if a == nil || b == nil {
+			return a != nil
+		}
+		return a.Before(*b)
+	})
+
+	report.Count = len(report.Resources)
+	report.GeneratedAt = now
+	return report
+}
+
+// pauseStart returns the earliest known time an object was paused
+func pauseStart(p models.PausedResource) *time.Time {
+	if p.PausedSince != nil && (p.PausedAt == nil || p.PausedSince.Before(*p.PausedAt)) {
+		return p.PausedSince
+	}
+	return p.PausedAt
+}
+
+// pausedBy returns the field manager owning the crossplane.io/paused annotation, its operation and its last update
+// When several managers own it, the most recent is returned
+func pausedBy(obj *unstructured.Unstructured) (string, string, *time.Time) {
+	var manager, operation string
+	var at *time.Time
+
+	for _, entry := range obj.GetManagedFields() {
+		if entry.FieldsV1 == nil || !ownsPausedAnnotation(entry.FieldsV1.Raw) {
+			continue
+		}
+		var entryTime *time.Time
+		if entry.Time != nil {
+			t := entry.Time.Time
+			entryTime = &t
+		}
+		if manager == "" || (entryTime != nil && (at == nil || entryTime.After(*at))) {
+			manager, operation, at = entry.Manager, string(entry.Operation), entryTime
+		}
+	}
+	return manager, operation, at
+}
+
+// ownsPausedAnnotation reports whether a managedFields set contains the crossplane.io/paused annotation
+func ownsPausedAnnotation(raw []byte) bool {
+	var fields struct {
+		Metadata struct {
+			Annotations map[string]json.RawMessage `json:"f:annotations"`
+		} `json:"f:metadata"`
+	}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return false
+	}
+	_, ok := fields.Metadata.Annotations["f:"+models.PausedAnnotation]
+	return ok
+}
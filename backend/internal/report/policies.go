@@ -0,0 +1,132 @@
+package report
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gravitek/crossplane-spy/internal/k8s"
+	"github.com/gravitek/crossplane-spy/internal/models"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Risky policy combinations flagged by the policy audit
+const (
+	// RuleProtectedDelete flags protected resources whose external resource is deleted with them
+	RuleProtectedDelete = "ProtectedDelete"
+	// RuleOrphanOverridden flags deletionPolicy: Orphan ignored because explicit management policies include Delete
+	RuleOrphanOverridden = "OrphanOverridden"
+	// RuleObserveWithDelete flags observe-only imports whose management policies still include Delete
+	RuleObserveWithDelete = "ObserveWithDelete"
+)
+
+// DefaultProtectedSelector selects the managed resources whose external resource must not be deleted
+const DefaultProtectedSelector = "protected=true"
+
+// PolicyAuditOptions filters the policy audit
+type PolicyAuditOptions struct {
+	// Provider and Namespace restrict the audit, empty means all
+	Provider  string
+	Namespace string
+	// Protected selects the resources whose external resource must survive their deletion
+	Protected labels.Selector
+}
+
+// PolicyAudit counts the managed resources per provider, namespace and effective management and deletion policies,
+// and flags risky combinations
+func PolicyAudit(objects []k8s.CachedObject, providers ProviderIndex, opts PolicyAuditOptions) models.PolicyAuditReport {
+	report := models.PolicyAuditReport{
+		Kind:         "PolicyAuditReport",
+		Combinations: []models.PolicyCombination{},
+		Findings:     []models.PolicyFinding{},
+	}
+
+	type combinationKey struct {
+		provider, namespace, managementPolicies, deletionPolicy string
+	}
+	combinations := make(map[combinationKey]*models.PolicyCombination)
+
+	for _, cached := range objects {
+		obj := cached.Object
+		provider := providers.ProviderFor(cached.GVR)
+		if (opts.Provider != "" && provider != opts.Provider) || (opts.Namespace != "" && obj.GetNamespace() != opts.Namespace) {
+			continue
+		}
+		report.ManagedCount++
+
+		managementPolicies, deletionPolicy := models.ConvertManagedPolicies(obj)
+		explicitPolicies := len(managementPolicies) > 0
+		if !explicitPolicies {
+			managementPolicies = models.DefaultManagementPolicies
+		}
+		if deletionPolicy == "" {
+			deletionPolicy = models.DefaultDeletionPolicy
+		}
+		managementPolicies = slices.Sorted(slices.Values(managementPolicies))
+		deletes := deletesExternal(managementPolicies, deletionPolicy)
+
+		key := combinationKey{provider, obj.GetNamespace(), strings.Join(managementPolicies, ","), deletionPolicy}
+		c, ok := combinations[key]
+		if !ok {
+			c = &models.PolicyCombination{
+				Provider:           provider,
+				Namespace:          obj.GetNamespace(),
+				ManagementPolicies: managementPolicies,
+				DeletionPolicy:     deletionPolicy,
+				DeletesExternal:    deletes,
+			}
+			combinations[key] = c
+		}
+		c.Count++
+
+		finding := func(rule, message string) {
+			report.Findings = append(report.Findings, models.PolicyFinding{
+				ObjectRef:          objectRef(obj),
+				Provider:           provider,
+				Rule:               rule,
+				Message:            message,
+				ManagementPolicies: managementPolicies,
+				DeletionPolicy:     deletionPolicy,
+			})
+		}
+		if deletes && opts.Protected != nil && !opts.Protected.Empty() && opts.Protected.Matches(labels.Set(obj.GetLabels())) {
+			finding(RuleProtectedDelete, "Resource is labeled as protected but deleting it deletes the external resource")
+		}
+		if explicitPolicies && deletionPolicy == "Orphan" && deletes {
+			finding(RuleOrphanOverridden, "deletionPolicy Orphan is ignored: the management policies include Delete and take precedence")
+		}
+		if slices.Contains(managementPolicies, "Observe") && !slices.Contains(managementPolicies, "Create") && !slices.Contains(managementPolicies, "Update") && deletes {
+			finding(RuleObserveWithDelete, "Observe-only import still deletes the external resource when the resource is deleted")
+		}
+	}
+
+	for _, c := range combinations {
+		report.Combinations = append(report.Combinations, *c)
+	}
+	sort.Slice(report.Combinations, func(i, j int) bool {
+		a, b := report.Combinations[i], report.Combinations[j]
+		if a.Provider != b.Provider {
+			return a.Provider < b.Provider
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Count > b.Count
+	})
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		return report.Findings[i].Rule < report.Findings[j].Rule
+	})
+
+	report.GeneratedAt = time.Now()
+	return report
+}
+
+// deletesExternal reports whether deleting a managed resource deletes its external resource
+// deletionPolicy only applies with the default management policies, explicit policies take precedence
+func deletesExternal(managementPolicies []string, deletionPolicy string) bool {
+	if slices.Equal(managementPolicies, models.DefaultManagementPolicies) {
+		return deletionPolicy != "Orphan"
+	}
+	return slices.Contains(managementPolicies, "Delete") || slices.Contains(managementPolicies, "*")
+}
@@ -0,0 +1,58 @@
+package report
+
+import (
+	"regexp"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// packageLabel is set by Crossplane on package revisions to the name of their package
+const packageLabel = "pkg.crossplane.io/package"
+
+// revisionSuffix is the hash Crossplane appends to a package name to name its revisions
+var revisionSuffix = regexp.MustCompile(`-[0-9a-f]{12}$`)
+
+// ProviderIndex maps the CRDs installed by providers to the provider name
+type ProviderIndex map[string]string
+
+// NewProviderIndex indexes the CRDs listed in status.objectRefs of ProviderRevisions
+// Active revisions take precedence over inactive ones installing the same CRD
+func NewProviderIndex(revisions []unstructured.Unstructured) ProviderIndex {
+	index := make(ProviderIndex)
+
+	for _, active := range []bool{false, true} {
+		for _, rev := range revisions {
+			state, _, _ := unstructured.NestedString(rev.Object, "spec", "desiredState")
+			if (state == "Active") != active {
+				continue
+			}
+
+			provider := rev.GetLabels()[packageLabel]
+			if provider == "" {
+				provider = revisionSuffix.ReplaceAllString(rev.GetName(), "")
+			}
+
+			refs, _, _ := unstructured.NestedSlice(rev.Object, "status", "objectRefs")
+			for _, r := range refs {
+				ref, ok := r.(map[string]interface{})
+				if !ok || ref["kind"] != "CustomResourceDefinition" {
+					continue
+				}
+				if name, ok := ref["name"].(string); ok {
+					index[name] = provider
+				}
+			}
+		}
+	}
+
+	return index
+}
+
+// ProviderFor returns the provider installing a resource type, or its API group when unknown
+func (i ProviderIndex) ProviderFor(gvr schema.GroupVersionResource) string {
+	if provider, ok := i[gvr.GroupResource().String()]; ok {
+		return provider
+	}
+	return gvr.Group
+}
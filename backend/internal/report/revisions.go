@@ -0,0 +1,162 @@
+package report
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gravitek/crossplane-spy/internal/models"
+	"github.com/gravitek/crossplane-spy/internal/snapshot"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// RevisionHistory converts the CompositionRevisions of a Composition, latest first,
+// each with its diff against the previous revision
+func RevisionHistory(revisions []unstructured.Unstructured) []models.CompositionRevision {
+	sorted := slices.Clone(revisions)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return revisionNumber(&sorted[i]) > revisionNumber(&sorted[j])
+	})
+
+	history := make([]models.CompositionRevision, 0, len(sorted))
+	for i := range sorted {
+		rev := convertRevision(&sorted[i])
+		rev.Latest = i == 0
+		if i+1 < len(sorted) {
+			rev.Diff = diffRevisions(&sorted[i+1], &sorted[i])
+		}
+		history = append(history, rev)
+	}
+	return history
+}
+
+// LatestRevisions returns the name of the latest CompositionRevision of each Composition
+func LatestRevisions(revisions []unstructured.Unstructured) map[string]string {
+	latest := make(map[string]string)
+	numbers := make(map[string]int64)
+	for i := range revisions {
+		composition := revisions[i].GetLabels()[models.CompositionNameLabel]
+		if composition == "" {
+			continue
+		}
+		if n := revisionNumber(&revisions[i]); latest[composition] == "" || n > numbers[composition] {
+			latest[composition] = revisions[i].GetName()
+			numbers[composition] = n
+		}
+	}
+	return latest
+}
+
+func revisionNumber(obj *unstructured.Unstructured) int64 {
+	n, _, _ := unstructured.NestedFieldNoCopy(obj.Object, "spec", "revision")
+	switch v := n.(type) {
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
+
+func convertRevision(obj *unstructured.Unstructured) models.CompositionRevision {
+	rev := models.CompositionRevision{
+		BaseResource: models.ConvertToBaseResource(obj, models.ScopeCluster),
+		Revision:     revisionNumber(obj),
+		Hash:         obj.GetLabels()[models.CompositionHashLabel],
+	}
+	rev.Mode, _, _ = unstructured.NestedString(obj.Object, "spec", "mode")
+	for _, item := range namedItems(obj, "pipeline", "step") {
+		rev.Steps = append(rev.Steps, item.name)
+	}
+	for _, item := range namedItems(obj, "resources", "name") {
+		rev.Resources = append(rev.Resources, item.name)
+	}
+	return rev
+}
+
+// diffRevisions compares the pipeline steps, resources and other spec fields of two revisions
+func diffRevisions(before, after *unstructured.Unstructured) *models.RevisionDiff {
+	diff := &models.RevisionDiff{
+		From:      revisionNumber(before),
+		Pipeline:  diffItems(namedItems(before, "pipeline", "step"), namedItems(after, "pipeline", "step")),
+		Resources: diffItems(namedItems(before, "resources", "name"), namedItems(after, "resources", "name")),
+	}
+
+	// The revision number and the composition content are compared separately
+	beforeSpec := otherSpecFields(before)
+	afterSpec := otherSpecFields(after)
+	diff.Spec = snapshot.DiffFields("spec", beforeSpec, afterSpec, []models.FieldChange{})
+	return diff
+}
+
+func otherSpecFields(obj *unstructured.Unstructured) map[string]interface{} {
+	spec, _, _ := unstructured.NestedMap(obj.Object, "spec")
+	delete(spec, "revision")
+	delete(spec, "pipeline")
+	delete(spec, "resources")
+	return spec
+}
+
+// namedItem is an entry of the pipeline or resources of a revision
+type namedItem struct {
+	name  string
+	value interface{}
+}
+
+// namedItems returns the entries of a spec list with their name field, unnamed entries are named by index
+func namedItems(obj *unstructured.Unstructured, list, nameField string) []namedItem {
+	items, _, _ := unstructured.NestedSlice(obj.Object, "spec", list)
+	named := make([]namedItem, 0, len(items))
+	for i, item := range items {
+		name := fmt.Sprintf("#%d", i)
+		if m, ok := item.(map[string]interface{}); ok {
+			if n, ok := m[nameField].(string); ok && n != "" {
+				name = n
+			}
+		}
+		named = append(named, namedItem{name: name, value: item})
+	}
+	return named
+}
+
+// diffItems matches items by name and reports the added, removed and changed ones,
+// and whether the items present in both lists changed order
+func diffItems(before, after []namedItem) models.ListDiff {
+	diff := models.ListDiff{Added: []string{}, Removed: []string{}, Changed: []models.ItemChange{}}
+
+	beforeByName := make(map[string]namedItem, len(before))
+	for _, item := range before {
+		beforeByName[item.name] = item
+	}
+	afterByName := make(map[string]bool, len(after))
+
+	var kept []string
+	for _, item := range after {
+		afterByName[item.name] = true
+		old, ok := beforeByName[item.name]
+		if !ok {
+			diff.Added = append(diff.Added, item.name)
+			continue
+		}
+		kept = append(kept, item.name)
+		if changes := snapshot.DiffFields("", old.value, item.value, nil); len(changes) > 0 {
+			for i := range changes {
+				changes[i].Path = strings.TrimPrefix(changes[i].Path, ".")
+			}
+			diff.Changed = append(diff.Changed, models.ItemChange{Name: item.name, Changes: changes})
+		}
+	}
+
+	var keptBefore []string
+	for _, item := range before {
+		if !afterByName[item.name] {
+			diff.Removed = append(diff.Removed, item.name)
+			continue
+		}
+		keptBefore = append(keptBefore, item.name)
+	}
+	diff.Reordered = !slices.Equal(kept, keptBefore)
+
+	return diff
+}
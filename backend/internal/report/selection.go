@@ -0,0 +1,154 @@
+package report
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/gravitek/crossplane-spy/internal/k8s"
+	"github.com/gravitek/crossplane-spy/internal/models"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// CompositionSelection explains how the Composition of a composite resource or claim is chosen: the enforced
+// Composition of its XRD, its compositionRef, its compositionSelector or the default Composition of its XRD
+// ok is false when no XRD defines the kind of the object
+func CompositionSelection(obj *unstructured.Unstructured, xrds, compositions []k8s.CachedObject) (selection models.CompositionSelection, ok bool) {
+	xrd := definingXRD(obj.GroupVersionKind(), xrds)
+	if xrd == nil {
+		return selection, false
+	}
+
+	selection.CompositeType = compositeType(xrd)
+	selection.Default, _, _ = unstructured.NestedString(xrd.Object, "spec", "defaultCompositionRef", "name")
+	selection.Enforced, _, _ = unstructured.NestedString(xrd.Object, "spec", "enforcedCompositionRef", "name")
+	if ref, _, _ := models.ConvertCompositionPinning(obj); ref != nil {
+		selection.Composition = ref.Name
+	}
+	selection.Selector = models.ConvertCompositionSelector(obj)
+	selection.Warnings = []string{}
+
+	switch {
+	case selection.Enforced != "":
+		selection.Method = models.SelectionEnforced
+		if selection.Composition != "" && selection.Composition != selection.Enforced {
+			selection.Warnings = append(selection.Warnings, fmt.Sprintf("compositionRef %q is overridden by the enforced Composition %q", selection.Composition, selection.Enforced))
+		}
+		selection.Composition = selection.Enforced
+
+	case selection.Composition != "":
+		// A compositionRef, set by the user or by an earlier selection, takes precedence over the selector
+		selection.Method = models.SelectionReference
+		if selection.Selector == nil && selection.Composition == selection.Default {
+			selection.Method = models.SelectionDefault
+		}
+		if selection.Selector != nil {
+			selection.Candidates = matchingCompositions(selection.Selector, selection.CompositeType, compositions)
+			if !slices.Contains(selection.Candidates, selection.Composition) {
+				selection.Warnings = append(selection.Warnings, fmt.Sprintf("The selected Composition %q does not match the compositionSelector, Crossplane does not select again", selection.Composition))
+			}
+		}
+
+	case selection.Selector != nil:
+		selection.Method = models.SelectionSelector
+		selection.Candidates = matchingCompositions(selection.Selector, selection.CompositeType, compositions)
+		switch {
+		case len(selection.Candidates) == 0:
+			selection.Warnings = append(selection.Warnings, "No Composition matches the compositionSelector")
+		case len(selection.Candidates) > 1:
+			selection.Warnings = append(selection.Warnings, fmt.Sprintf("%d Compositions match the compositionSelector, Crossplane selects one of them at random", len(selection.Candidates)))
+		}
+
+	case selection.Default != "":
+		selection.Method = models.SelectionDefault
+		selection.Composition = selection.Default
+
+	default:
+		selection.Method = models.SelectionNone
+		selection.Warnings = append(selection.Warnings, "Nothing selects a Composition: set a compositionRef, a compositionSelector or the defaultCompositionRef of the XRD")
+	}
+
+	if selection.Composition != "" {
+		selection.Warnings = append(selection.Warnings, checkComposition(selection.Composition, selection.CompositeType, compositions)...)
+	}
+	return selection, true
+}
+
+// definingXRD returns the XRD defining a composite resource or claim kind
+func definingXRD(gvk schema.GroupVersionKind, xrds []k8s.CachedObject) *unstructured.Unstructured {
+	for _, cached := range xrds {
+		group, _, _ := unstructured.NestedString(cached.Object.Object, "spec", "group")
+		if group != gvk.Group {
+			continue
+		}
+		kind, _, _ := unstructured.NestedString(cached.Object.Object, "spec", "names", "kind")
+		claimKind, _, _ := unstructured.NestedString(cached.Object.Object, "spec", "claimNames", "kind")
+		if gvk.Kind == kind || (claimKind != "" && gvk.Kind == claimKind) {
+			return cached.Object
+		}
+	}
+	return nil
+}
+
+// compositeType returns the composite resource type of an XRD in its referenceable version
+func compositeType(xrd *unstructured.Unstructured) models.TypeReference {
+	group, _, _ := unstructured.NestedString(xrd.Object, "spec", "group")
+	kind, _, _ := unstructured.NestedString(xrd.Object, "spec", "names", "kind")
+
+	version := ""
+	versions, _, _ := unstructured.NestedSlice(xrd.Object, "spec", "versions")
+	for _, v := range versions {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := m["name"].(string)
+		if version == "" {
+			version = name
+		}
+		if referenceable, _ := m["referenceable"].(bool); referenceable {
+			version = name
+			break
+		}
+	}
+	return models.TypeReference{APIVersion: schema.GroupVersion{Group: group, Version: version}.String(), Kind: kind}
+}
+
+// matchingCompositions returns the names of the Compositions of a composite type matching selector labels
+func matchingCompositions(selector map[string]string, typ models.TypeReference, compositions []k8s.CachedObject) []string {
+	matches := []string{}
+	set := labels.SelectorFromSet(selector)
+	for _, cached := range compositions {
+		if composesType(cached.Object, typ) && set.Matches(labels.Set(cached.Object.GetLabels())) {
+			matches = append(matches, cached.Object.GetName())
+		}
+	}
+	sort.Strings(matches)
+	return matches
+}
+
+// checkComposition warns when the selected Composition does not exist or composes another type
+func checkComposition(name string, typ models.TypeReference, compositions []k8s.CachedObject) []string {
+	for _, cached := range compositions {
+		if cached.Object.GetName() != name {
+			continue
+		}
+		if !composesType(cached.Object, typ) {
+			apiVersion, _, _ := unstructured.NestedString(cached.Object.Object, "spec", "compositeTypeRef", "apiVersion")
+			kind, _, _ := unstructured.NestedString(cached.Object.Object, "spec", "compositeTypeRef", "kind")
+			return []string{fmt.Sprintf("Composition %q composes %s %s, not %s %s", name, apiVersion, kind, typ.APIVersion, typ.Kind)}
+		}
+		return nil
+	}
+	return []string{fmt.Sprintf("Composition %q not found", name)}
+}
+
+// composesType reports whether a Composition composes a type, regardless of its version
+func composesType(composition *unstructured.Unstructured, typ models.TypeReference) bool {
+	apiVersion, _, _ := unstructured.NestedString(composition.Object, "spec", "compositeTypeRef", "apiVersion")
+	kind, _, _ := unstructured.NestedString(composition.Object, "spec", "compositeTypeRef", "kind")
+	return kind == typ.Kind &&
+		schema.FromAPIVersionAndKind(apiVersion, kind).Group == schema.FromAPIVersionAndKind(typ.APIVersion, typ.Kind).Group
+}
@@ -0,0 +1,128 @@
+package report
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/gravitek/crossplane-spy/internal/k8s"
+	"github.com/gravitek/crossplane-spy/internal/models"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestCompositionSelection(t *testing.T) {
+	xrd := func(spec map[string]interface{}) k8s.CachedObject {
+		spec["group"] = "example.org"
+		spec["names"] = map[string]interface{}{"kind": "XNetwork", "plural": "xnetworks"}
+		spec["claimNames"] = map[string]interface{}{"kind": "Network", "plural": "networks"}
+		spec["versions"] = []interface{}{map[string]interface{}{"name": "v1alpha1", "referenceable": true}}
+		return k8s.CachedObject{Object: &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apiextensions.crossplane.io/v1", "kind": "CompositeResourceDefinition",
+			"metadata": map[string]interface{}{"name": "xnetworks.example.org"},
+			"spec":     spec,
+		}}}
+	}
+	composition := func(name, kind string, labels map[string]interface{}) k8s.CachedObject {
+		return k8s.CachedObject{Object: &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apiextensions.crossplane.io/v1", "kind": "Composition",
+			"metadata": map[string]interface{}{"name": name, "labels": labels},
+			"spec":     map[string]interface{}{"compositeTypeRef": map[string]interface{}{"apiVersion": "example.org/v1alpha1", "kind": kind}},
+		}}}
+	}
+	xr := func(kind string, spec map[string]interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "example.org/v1alpha1", "kind": kind,
+			"metadata": map[string]interface{}{"name": "net-1"},
+			"spec":     spec,
+		}}
+	}
+	compositions := []k8s.CachedObject{
+		composition("aws", "XNetwork", map[string]interface{}{"provider": "aws", "region": "eu"}),
+		composition("aws-us", "XNetwork", map[string]interface{}{"provider": "aws", "region": "us"}),
+		composition("gcp", "XNetwork", map[string]interface{}{"provider": "gcp"}),
+		composition("subnet", "XSubnet", map[string]interface{}{"provider": "aws"}),
+	}
+	selector := func(labels map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"compositionSelector": map[string]interface{}{"matchLabels": labels}}
+	}
+
+	for _, tc := range []struct {
+		name        string
+		xrd         map[string]interface{}
+		obj         *unstructured.Unstructured
+		method      string
+		composition string
+		candidates  []string
+		warnings    int
+	}{
+		{
+			name: "reference", xrd: map[string]interface{}{},
+			obj:    xr("XNetwork", map[string]interface{}{"compositionRef": map[string]interface{}{"name": "gcp"}}),
+			method: models.SelectionReference, composition: "gcp",
+		},
+		{
+			name: "single match", xrd: map[string]interface{}{},
+			obj:    xr("Network", selector(map[string]interface{}{"provider": "aws", "region": "eu"})),
+			method: models.SelectionSelector, candidates: []string{"aws"},
+		},
+		{
+			name: "multiple matches of the composite type", xrd: map[string]interface{}{},
+			obj:    xr("XNetwork", selector(map[string]interface{}{"provider": "aws"})),
+			method: models.SelectionSelector, candidates: []string{"aws", "aws-us"}, warnings: 1,
+		},
+		{
+			name: "no match", xrd: map[string]interface{}{},
+			obj:    xr("XNetwork", selector(map[string]interface{}{"provider": "azure"})),
+			method: models.SelectionSelector, candidates: []string{}, warnings: 1,
+		},
+		{
+			name: "reference over selector", xrd: map[string]interface{}{},
+			obj: xr("XNetwork", map[string]interface{}{
+				"compositionRef":      map[string]interface{}{"name": "aws-us"},
+				"compositionSelector": map[string]interface{}{"matchLabels": map[string]interface{}{"provider": "aws"}},
+			}),
+			method: models.SelectionReference, composition: "aws-us", candidates: []string{"aws", "aws-us"},
+		},
+		{
+			name: "reference no longer matching the selector", xrd: map[string]interface{}{"defaultCompositionRef": map[string]interface{}{"name": "gcp"}},
+			obj: xr("XNetwork", map[string]interface{}{
+				"compositionRef":      map[string]interface{}{"name": "gcp"},
+				"compositionSelector": map[string]interface{}{"matchLabels": map[string]interface{}{"provider": "aws"}},
+			}),
+			method: models.SelectionReference, composition: "gcp", candidates: []string{"aws", "aws-us"}, warnings: 1,
+		},
+		{
+			name: "default", xrd: map[string]interface{}{"defaultCompositionRef": map[string]interface{}{"name": "aws"}},
+			obj:    xr("XNetwork", map[string]interface{}{}),
+			method: models.SelectionDefault, composition: "aws",
+		},
+		{
+			name: "enforced over reference", xrd: map[string]interface{}{"enforcedCompositionRef": map[string]interface{}{"name": "aws"}},
+			obj:    xr("XNetwork", map[string]interface{}{"compositionRef": map[string]interface{}{"name": "gcp"}}),
+			method: models.SelectionEnforced, composition: "aws", warnings: 1,
+		},
+		{
+			name: "reference to another composite type", xrd: map[string]interface{}{},
+			obj:    xr("XNetwork", map[string]interface{}{"compositionRef": map[string]interface{}{"name": "subnet"}}),
+			method: models.SelectionReference, composition: "subnet", warnings: 1,
+		},
+		{
+			name: "nothing", xrd: map[string]interface{}{},
+			obj:    xr("XNetwork", map[string]interface{}{}),
+			method: models.SelectionNone, warnings: 1,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := CompositionSelection(tc.obj, []k8s.CachedObject{xrd(tc.xrd)}, compositions)
+			if !ok {
+				t.Fatal("no XRD found")
+			}
+			if got.Method != tc.method || got.Composition != tc.composition || !slices.Equal(got.Candidates, tc.candidates) || len(got.Warnings) != tc.warnings {
+				t.Errorf("got %+v", got)
+			}
+		})
+	}
+
+	if _, ok := CompositionSelection(xr("XSubnet", map[string]interface{}{}), []k8s.CachedObject{xrd(map[string]interface{}{})}, compositions); ok {
+		t.Error("got a selection for a kind defined by no XRD")
+	}
+}
@@ -0,0 +1,165 @@
+package report
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gravitek/crossplane-spy/internal/history"
+	"github.com/gravitek/crossplane-spy/internal/k8s"
+	"github.com/gravitek/crossplane-spy/internal/models"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// DefaultMinUpdates is the number of spec updates in the churn window from which a ready resource is drifting
+const DefaultMinUpdates = 3
+
+// maxResourcesPerGroup bounds the sample of resources listed per failure group
+const maxResourcesPerGroup = 20
+
+// SyncFailureOptions filters the sync failure report
+type SyncFailureOptions struct {
+	// Provider and Kind (case-insensitive) restrict the report to one provider or kind, empty means all
+	Provider string
+	Kind     string
+	// MinUpdates is the number of spec updates in the churn window above which a ready resource is drifting
+	MinUpdates int
+}
+
+// SyncFailures groups the managed resources with Synced=False by provider, kind, reason and normalized message,
+// and lists the ready managed resources updated at least MinUpdates times in the churn window
+func SyncFailures(objects []k8s.CachedObject, providers ProviderIndex, churn *ChurnTracker, opts SyncFailureOptions) models.SyncFailureReport {
+	report := models.SyncFailureReport{
+		Kind:     "SyncFailureReport",
+		Groups:   []models.SyncFailureGroup{},
+		Drifting: []models.DriftingResource{},
+	}
+
+	type groupKey struct {
+		provider, group, kind, reason, message string
+	}
+	groups := make(map[groupKey]*models.SyncFailureGroup)
+
+	for _, cached := range objects {
+		obj := cached.Object
+		provider := providers.ProviderFor(cached.GVR)
+		if (opts.Provider != "" && provider != opts.Provider) || (opts.Kind != "" && !strings.EqualFold(obj.GetKind(), opts.Kind)) {
+			continue
+		}
+		report.ManagedCount++
+
+		status := models.ConvertToResourceStatus(obj)
+		synced, hasSynced := findCondition(status.Conditions, "Synced")
+
+		if hasSynced && synced.Status == "False" {
+			report.UnsyncedCount++
+
+			key := groupKey{
+				provider: provider,
+				group:    cached.GVR.Group,
+				kind:     obj.GetKind(),
+				reason:   synced.Reason,
+				message:  NormalizeMessage(synced.Message, obj.GetName(), obj.GetAnnotations()[models.ExternalNameAnnotation]),
+			}
+			g, ok := groups[key]
+			if !ok {
+				g = &models.SyncFailureGroup{
+					Provider:      key.provider,
+					Group:         key.group,
+					Kind:          key.kind,
+					Reason:        key.reason,
+					Message:       key.message,
+					SampleMessage: synced.Message,
+				}
+				groups[key] = g
+			}
+
+			g.Count++
+			if g.OldestFailure.IsZero() || (!synced.LastTransitionTime.IsZero() && synced.LastTransitionTime.Before(g.OldestFailure)) {
+				g.OldestFailure = synced.LastTransitionTime
+			}
+			if pc, found, _ := unstructured.NestedString(obj.Object, "spec", "providerConfigRef", "name"); found {
+				if g.ProviderConfigs == nil {
+					g.ProviderConfigs = make(map[string]int)
+				}
+				g.ProviderConfigs[pc]++
+			}
+			if len(g.Resources) < maxResourcesPerGroup {
+				g.Resources = append(g.Resources, objectRef(obj))
+			}
+			continue
+		}
+
+		if churn == nil || !status.Ready {
+			continue
+		}
+		updates := churn.Updates(history.KeyForObject(obj))
+		if len(updates) > 0 && len(updates) >= opts.MinUpdates {
+			report.Drifting = append(report.Drifting, models.DriftingResource{
+				ObjectRef:  objectRef(obj),
+				Provider:   provider,
+				Updates:    len(updates),
+				LastUpdate: updates[len(updates)-1],
+			})
+		}
+	}
+
+	for _, g := range groups {
+		report.Groups = append(report.Groups, *g)
+	}
+	sort.SliceStable(report.Groups, func(i, j int) bool {
+		a, b := report.Groups[i], report.Groups[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if !a.OldestFailure.Equal(b.OldestFailure) {
+			return a.OldestFailure.Before(b.OldestFailure)
+		}
+		if a.Provider != b.Provider {
+			return a.Provider < b.Provider
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Reason != b.Reason {
+			return a.Reason < b.Reason
+		}
+		return a.Message < b.Message
+	})
+	sort.SliceStable(report.Drifting, func(i, j int) bool {
+		a, b := report.Drifting[i], report.Drifting[j]
+		if a.Updates != b.Updates {
+			return a.Updates > b.Updates
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Kind < b.Kind
+	})
+
+	report.GeneratedAt = time.Now()
+	return report
+}
+
+// findCondition returns the condition of the given type
+func findCondition(conditions []models.Condition, conditionType string) (models.Condition, bool) {
+	for _, c := range conditions {
+		if c.Type == conditionType {
+			return c, true
+		}
+	}
+	return models.Condition{}, false
+}
+
+// objectRef returns the reference of an object
+func objectRef(obj *unstructured.Unstructured) models.ObjectRef {
+	return models.ObjectRef{
+		APIVersion: obj.GetAPIVersion(),
+		Kind:       obj.GetKind(),
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+	}
+}
@@ -0,0 +1,69 @@
+package report
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gravitek/crossplane-spy/internal/k8s"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// TestSyncFailuresOrder checks that groups and drifting resources with the same counts
+// are ordered the same way whatever the order of the cached objects
+func TestSyncFailuresOrder(t *testing.T) {
+	managed := func(kind, namespace, name, status, reason string) k8s.CachedObject {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "s3.aws.upbound.io/v1beta1", "kind": kind,
+			"metadata": map[string]interface{}{"name": name, "namespace": namespace, "generation": int64(1)},
+			"status": map[string]interface{}{"conditions": []interface{}{
+				map[string]interface{}{"type": "Synced", "status": status, "reason": reason},
+				map[string]interface{}{"type": "Ready", "status": "True"},
+			}},
+		}}
+		return k8s.CachedObject{Object: obj}
+	}
+	objects := []k8s.CachedObject{
+		managed("Bucket", "", "logs", "False", "ReconcileError"),
+		managed("BucketPolicy", "", "logs", "False", "ReconcileError"),
+		managed("Bucket", "", "assets", "False", "CannotObserve"),
+		managed("Bucket", "team-b", "data", "True", "ReconcileSuccess"),
+		managed("Bucket", "team-a", "data", "True", "ReconcileSuccess"),
+		managed("Bucket", "", "archive", "True", "ReconcileSuccess"),
+	}
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	churn := NewChurnTracker(time.Hour)
+	churn.now = func() time.Time { return now }
+	for _, cached := range objects[3:] {
+		updated := cached.Object.DeepCopy()
+		updated.SetGeneration(2)
+		churn.OnUpdate(cached.Object, updated)
+	}
+
+	wantGroups := []string{"Bucket/CannotObserve", "Bucket/ReconcileError", "BucketPolicy/ReconcileError"}
+	wantDrifting := []string{"/archive", "team-a/data", "team-b/data"}
+
+	for _, reversed := range []bool{false, true} {
+		ordered := slices.Clone(objects)
+		if reversed {
+			slices.Reverse(ordered)
+		}
+
+		report := SyncFailures(ordered, ProviderIndex{}, churn, SyncFailureOptions{MinUpdates: 1})
+
+		var groups, drifting []string
+		for _, g := range report.Groups {
+			groups = append(groups, g.Kind+"/"+g.Reason)
+		}
+		for _, d := range report.Drifting {
+			drifting = append(drifting, d.Namespace+"/"+d.Name)
+		}
+		if !slices.Equal(groups, wantGroups) {
+			t.Errorf("reversed %v: got groups %v, want %v", reversed, groups, wantGroups)
+		}
+		if !slices.Equal(drifting, wantDrifting) {
+			t.Errorf("reversed %v: got drifting resources %v, want %v", reversed, drifting, wantDrifting)
+		}
+	}
+}
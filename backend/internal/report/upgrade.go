@@ -0,0 +1,194 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gravitek/crossplane-spy/internal/k8s"
+	"github.com/gravitek/crossplane-spy/internal/models"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Crossplane v1 constructs flagged by the upgrade report
+const (
+	// RuleControllerConfig flags ControllerConfigs, replaced by DeploymentRuntimeConfigs
+	RuleControllerConfig = "ControllerConfig"
+	// RuleControllerConfigRef flags packages configured by a ControllerConfig
+	RuleControllerConfigRef = "ControllerConfigRef"
+	// RuleUnqualifiedPackage flags package images relying on the default registry, which Crossplane v2 dropped
+	RuleUnqualifiedPackage = "UnqualifiedPackage"
+	// RuleNativePatchAndTransform flags Compositions in mode: Resources
+	RuleNativePatchAndTransform = "NativePatchAndTransform"
+	// RuleNativeEnvironment flags Compositions selecting EnvironmentConfigs with spec.environment
+	RuleNativeEnvironment = "NativeEnvironment"
+	// RuleExternalSecretStore flags connection details published to external secret stores
+	RuleExternalSecretStore = "ExternalSecretStore"
+	// RuleLegacyXRD flags XRDs of legacy cluster-scoped composite resources
+	RuleLegacyXRD = "LegacyXRD"
+	// RuleClaimNames flags XRDs offering claims
+	RuleClaimNames = "ClaimNames"
+	// RuleClaim flags claims
+	RuleClaim = "Claim"
+	// RuleLegacyComposite flags legacy cluster-scoped composite resources
+	RuleLegacyComposite = "LegacyComposite"
+	// RuleLegacyUsage flags the Usages of apiextensions.crossplane.io, moved to protection.crossplane.io
+	RuleLegacyUsage = "LegacyUsage"
+)
+
+// Types checked by the upgrade report that the object cache does not watch
+var (
+	controllerConfigKind = schema.GroupKind{Group: k8s.ProviderGVR.Group, Kind: "ControllerConfig"}
+	configurationKind    = schema.GroupKind{Group: k8s.ProviderGVR.Group, Kind: "Configuration"}
+)
+
+// legacyXRDScope is the scope Crossplane v2 gives to the XRDs of apiextensions.crossplane.io/v1
+const legacyXRDScope = "LegacyCluster"
+
+// V2Upgrade inventories the Crossplane v1 constructs deprecated or removed in Crossplane v2, grouped by object,
+// objects blocking the upgrade first
+// packages are the ControllerConfigs and Configurations, which the object cache does not watch
+func V2Upgrade(objects []k8s.CachedObject, packages []unstructured.Unstructured) models.UpgradeReport {
+	report := models.UpgradeReport{
+		Kind:    "UpgradeReport",
+		Rules:   map[string]int{},
+		Objects: []models.UpgradeObject{},
+	}
+
+	add := func(category string, obj *unstructured.Unstructured) {
+		findings := upgradeFindings(category, obj)
+		if len(findings) == 0 {
+			return
+		}
+		object := models.UpgradeObject{ObjectRef: objectRef(obj), Findings: findings}
+		for _, finding := range findings {
+			report.Rules[finding.Rule]++
+			if finding.Severity == models.UpgradeRemoved {
+				report.Removed++
+				object.Blocking = true
+			} else {
+				report.Deprecated++
+			}
+		}
+		report.Objects = append(report.Objects, object)
+	}
+	for _, cached := range objects {
+		add(cached.Category, cached.Object)
+	}
+	for i := range packages {
+		add("", &packages[i])
+	}
+
+	sort.SliceStable(report.Objects, func(i, j int) bool {
+		a, b := report.Objects[i], report.Objects[j]
+		if a.Blocking != b.Blocking {
+			return a.Blocking
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	report.Ready = report.Removed == 0
+	report.GeneratedAt = time.Now()
+	return report
+}
+
+// upgradeFindings returns the v1 constructs used by an object
+func upgradeFindings(category string, obj *unstructured.Unstructured) []models.UpgradeFinding {
+	var findings []models.UpgradeFinding
+	add := func(rule, severity, message, action string) {
+		findings = append(findings, models.UpgradeFinding{Rule: rule, Severity: severity, Message: message, Action: action})
+	}
+
+	gk := obj.GroupVersionKind().GroupKind()
+	switch {
+	case gk == controllerConfigKind:
+		add(RuleControllerConfig, models.UpgradeRemoved,
+			"ControllerConfig was removed in Crossplane v2",
+			"Replace it with a DeploymentRuntimeConfig")
+
+	case category == k8s.CategoryProvider || category == k8s.CategoryFunction || gk == configurationKind:
+		if name, _, _ := unstructured.NestedString(obj.Object, "spec", "controllerConfigRef", "name"); name != "" {
+			add(RuleControllerConfigRef, models.UpgradeRemoved,
+				fmt.Sprintf("spec.controllerConfigRef references ControllerConfig %q", name),
+				"Reference a DeploymentRuntimeConfig with spec.runtimeConfigRef")
+		}
+		if pkg, _, _ := unstructured.NestedString(obj.Object, "spec", "package"); pkg != "" && !qualifiedPackage(pkg) {
+			add(RuleUnqualifiedPackage, models.UpgradeRemoved,
+				fmt.Sprintf("Package %q has no registry, Crossplane v2 has no default registry", pkg),
+				fmt.Sprintf("Use the fully qualified image, e.g. xpkg.crossplane.io/%s", pkg))
+		}
+
+	case category == k8s.CategoryXRD:
+		// v1 XRDs have no scope, v2 XRDs default to Namespaced
+		scope, _, _ := unstructured.NestedString(obj.Object, "spec", "scope")
+		if scope == legacyXRDScope || (scope == "" && obj.GroupVersionKind().Version == "v1") {
+			add(RuleLegacyXRD, models.UpgradeDeprecated,
+				"Its composite resources are legacy cluster-scoped XRs",
+				"Define an apiextensions.crossplane.io/v2 XRD with scope Namespaced or Cluster")
+		}
+		if kind, _, _ := unstructured.NestedString(obj.Object, "spec", "claimNames", "kind"); kind != "" {
+			add(RuleClaimNames, models.UpgradeDeprecated,
+				fmt.Sprintf("It offers the %s claim, claims are only supported for legacy cluster-scoped XRs", kind),
+				"Create namespaced XRs instead of claims")
+		}
+
+	case category == k8s.CategoryComposition:
+		mode, _, _ := unstructured.NestedString(obj.Object, "spec", "mode")
+		_, hasResources, _ := unstructured.NestedSlice(obj.Object, "spec", "resources")
+		if mode == modeResources || (mode == "" && hasResources) {
+			add(RuleNativePatchAndTransform, models.UpgradeRemoved,
+				"Native patch and transform (mode: Resources) was removed in Crossplane v2",
+				"Convert it to a function-patch-and-transform pipeline, e.g. with crossplane beta convert pipeline-composition")
+		}
+		if _, found, _ := unstructured.NestedFieldNoCopy(obj.Object, "spec", "environment"); found {
+			add(RuleNativeEnvironment, models.UpgradeRemoved,
+				"spec.environment was removed in Crossplane v2",
+				"Select the EnvironmentConfigs with a function-environment-configs pipeline step")
+		}
+		if _, found, _ := unstructured.NestedFieldNoCopy(obj.Object, "spec", "publishConnectionDetailsWithStoreConfigRef"); found {
+			add(RuleExternalSecretStore, models.UpgradeRemoved,
+				"External secret stores were removed in Crossplane v2",
+				"Remove spec.publishConnectionDetailsWithStoreConfigRef and write connection secrets to Kubernetes Secrets")
+		}
+
+	case category == k8s.CategoryComposite:
+		if _, v2, _ := unstructured.NestedMap(obj.Object, "spec", "crossplane"); obj.GetNamespace() == "" && !v2 {
+			add(RuleLegacyComposite, models.UpgradeDeprecated,
+				"Legacy cluster-scoped composite resource",
+				"Recreate it as a namespaced XR of an apiextensions.crossplane.io/v2 XRD")
+		}
+
+	case category == k8s.CategoryClaim:
+		add(RuleClaim, models.UpgradeDeprecated,
+			"Claims are only supported for legacy cluster-scoped XRs",
+			"Replace it with a namespaced XR")
+
+	case category == k8s.CategoryUsage:
+		if gk.Group == k8s.CompositionGVR.Group {
+			add(RuleLegacyUsage, models.UpgradeDeprecated,
+				fmt.Sprintf("Usage of %s is deprecated", k8s.CompositionGVR.Group),
+				"Use the Usage or ClusterUsage of protection.crossplane.io")
+		}
+	}
+
+	// Managed resources, composite resources and claims publishing to external secret stores
+	if _, found, _ := unstructured.NestedFieldNoCopy(obj.Object, "spec", "publishConnectionDetailsTo"); found {
+		add(RuleExternalSecretStore, models.UpgradeRemoved,
+			"External secret stores were removed in Crossplane v2",
+			"Replace spec.publishConnectionDetailsTo with spec.writeConnectionSecretToRef")
+	}
+	return findings
+}
+
+// qualifiedPackage reports whether a package image names its registry, as in Docker references
+func qualifiedPackage(pkg string) bool {
+	registry, _, found := strings.Cut(pkg, "/")
+	return found && (strings.ContainsAny(registry, ".:") || registry == "localhost")
+}
@@ -0,0 +1,109 @@
+package report
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/gravitek/crossplane-spy/internal/k8s"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestV2Upgrade(t *testing.T) {
+	object := func(apiVersion, kind, namespace, name string, spec map[string]interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": apiVersion, "kind": kind,
+			"metadata": map[string]interface{}{"name": name, "namespace": namespace},
+			"spec":     spec,
+		}}
+	}
+	cached := func(category string, obj *unstructured.Unstructured) k8s.CachedObject {
+		return k8s.CachedObject{CachedKind: k8s.CachedKind{Category: category}, Object: obj}
+	}
+
+	for _, tc := range []struct {
+		name     string
+		category string
+		obj      *unstructured.Unstructured
+		want     []string
+	}{
+		{
+			name: "provider with a ControllerConfig and the default registry", category: k8s.CategoryProvider,
+			obj: object("pkg.crossplane.io/v1", "Provider", "", "provider-aws", map[string]interface{}{
+				"package":             "crossplane-contrib/provider-aws:v0.47.0",
+				"controllerConfigRef": map[string]interface{}{"name": "debug"},
+			}),
+			want: []string{RuleControllerConfigRef, RuleUnqualifiedPackage},
+		},
+		{
+			name: "function from a registry", category: k8s.CategoryFunction,
+			obj:  object("pkg.crossplane.io/v1", "Function", "", "function-kcl", map[string]interface{}{"package": "localhost:5000/function-kcl:v0.1.0"}),
+			want: nil,
+		},
+		{
+			name: "configuration", category: "",
+			obj:  object("pkg.crossplane.io/v1", "Configuration", "", "platform", map[string]interface{}{"package": "platform:v1"}),
+			want: []string{RuleUnqualifiedPackage},
+		},
+		{
+			name: "v2 XRD", category: k8s.CategoryXRD,
+			obj:  object("apiextensions.crossplane.io/v2", "CompositeResourceDefinition", "", "xapps.example.org", map[string]interface{}{"scope": "Namespaced"}),
+			want: nil,
+		},
+		{
+			name: "v2 XRD with the default scope", category: k8s.CategoryXRD,
+			obj:  object("apiextensions.crossplane.io/v2", "CompositeResourceDefinition", "", "xdbs.example.org", map[string]interface{}{}),
+			want: nil,
+		},
+		{
+			name: "v1 XRD", category: k8s.CategoryXRD,
+			obj:  object("apiextensions.crossplane.io/v1", "CompositeResourceDefinition", "", "xnetworks.example.org", map[string]interface{}{}),
+			want: []string{RuleLegacyXRD},
+		},
+		{
+			name: "pipeline composition with native environment and secret store", category: k8s.CategoryComposition,
+			obj: object("apiextensions.crossplane.io/v1", "Composition", "", "apps", map[string]interface{}{
+				"mode":        "Pipeline",
+				"environment": map[string]interface{}{"environmentConfigs": []interface{}{}},
+				"publishConnectionDetailsWithStoreConfigRef": map[string]interface{}{"name": "vault"},
+			}),
+			want: []string{RuleNativeEnvironment, RuleExternalSecretStore},
+		},
+		{
+			name: "managed resource publishing to a secret store", category: k8s.CategoryManaged,
+			obj: object("s3.aws.upbound.io/v1beta1", "Bucket", "", "logs", map[string]interface{}{
+				"publishConnectionDetailsTo": map[string]interface{}{"name": "logs"},
+			}),
+			want: []string{RuleExternalSecretStore},
+		},
+		{
+			name: "v2 cluster-scoped XR", category: k8s.CategoryComposite,
+			obj:  object("example.org/v1", "XApp", "", "app", map[string]interface{}{"crossplane": map[string]interface{}{}}),
+			want: nil,
+		},
+		{
+			name: "v2 usage", category: k8s.CategoryUsage,
+			obj:  object("protection.crossplane.io/v1beta1", "Usage", "default", "protect", map[string]interface{}{}),
+			want: nil,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := V2Upgrade([]k8s.CachedObject{cached(tc.category, tc.obj)}, nil)
+			if tc.category == "" {
+				r = V2Upgrade(nil, []unstructured.Unstructured{*tc.obj})
+			}
+
+			var got []string
+			for _, o := range r.Objects {
+				for _, f := range o.Findings {
+					got = append(got, f.Rule)
+				}
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("got findings %v, want %v", got, tc.want)
+			}
+			if r.Ready != (r.Removed == 0) || r.Removed+r.Deprecated != len(got) {
+				t.Errorf("got ready %v with %d removed and %d deprecated", r.Ready, r.Removed, r.Deprecated)
+			}
+		})
+	}
+}
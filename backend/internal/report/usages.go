@@ -0,0 +1,56 @@
+package report
+
+import (
+	"github.com/gravitek/crossplane-spy/internal/k8s"
+	"github.com/gravitek/crossplane-spy/internal/models"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// usageTarget identifies an object referenced by a Usage
+type usageTarget struct {
+	groupKind schema.GroupKind
+	namespace string
+	name      string
+}
+
+// targetOf returns the index key of a referenced object
+func targetOf(ref models.ObjectRef) usageTarget {
+	return usageTarget{groupKind: schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind).GroupKind(), namespace: ref.Namespace, name: ref.Name}
+}
+
+// UsageIndex finds the Usages protecting an object from deletion, and those an object is the user of
+type UsageIndex struct {
+	of map[usageTarget][]models.Usage
+	by map[usageTarget][]models.Usage
+}
+
+// NewUsageIndex indexes Usages and ClusterUsages by the objects of their spec.of and spec.by references
+// Usages selecting their objects by labels are indexed once Crossplane has resolved the selector into a resourceRef
+func NewUsageIndex(usages []k8s.CachedObject) UsageIndex {
+	index := UsageIndex{
+		of: make(map[usageTarget][]models.Usage),
+		by: make(map[usageTarget][]models.Usage),
+	}
+	for _, cached := range usages {
+		usage := models.ConvertToUsage(cached.Object)
+		if usage.Spec.Of.Resolved() {
+			key := targetOf(usage.Spec.Of.ObjectRef)
+			index.of[key] = append(index.of[key], usage)
+		}
+		if usage.Spec.By != nil && usage.Spec.By.Resolved() {
+			key := targetOf(usage.Spec.By.ObjectRef)
+			index.by[key] = append(index.by[key], usage)
+		}
+	}
+	return index
+}
+
+// Protecting returns the Usages of the referenced object
+func (i UsageIndex) Protecting(ref models.ObjectRef) []models.Usage {
+	return i.of[targetOf(ref)]
+}
+
+// UsedBy returns the Usages whose user is the referenced object
+func (i UsageIndex) UsedBy(ref models.ObjectRef) []models.Usage {
+	return i.by[targetOf(ref)]
+}
@@ -0,0 +1,83 @@
+package snapshot
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/gravitek/crossplane-spy/internal/k8s"
+	"github.com/gravitek/crossplane-spy/internal/models"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// idFormat is the layout of the time prefix of snapshot IDs, which sort chronologically
+const idFormat = "20060102-150405.000"
+
+// newID returns a snapshot ID made of the capture time and a random suffix,
+// so that snapshots captured in the same millisecond do not replace each other
+func newID(now time.Time) string {
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	return now.UTC().Format(idFormat) + "-" + hex.EncodeToString(suffix)
+}
+
+// Capture builds a snapshot of every object in the object cache
+func Capture(objectCache *k8s.ObjectCache, trigger string, now time.Time) models.Snapshot {
+	cached := objectCache.Objects()
+
+	objects := make([]models.SnapshotObject, 0, len(cached))
+	for _, c := range cached {
+		spec, _, _ := unstructured.NestedMap(c.Object.Object, "spec")
+
+		scope := models.ScopeCluster
+		if c.Object.GetNamespace() != "" {
+			scope = models.ScopeNamespace
+		}
+
+		objects = append(objects, models.SnapshotObject{
+			BaseResource: models.ConvertToBaseResource(c.Object, scope),
+			Category:     c.Category,
+			Status:       models.ConvertToResourceStatus(c.Object),
+			Spec:         spec,
+		})
+	}
+
+	sort.Slice(objects, func(i, j int) bool {
+		return objectID(refOf(objects[i])) < objectID(refOf(objects[j]))
+	})
+
+	return models.Snapshot{
+		ID:        newID(now),
+		CreatedAt: now,
+		Trigger:   trigger,
+		Count:     len(objects),
+		Objects:   objects,
+	}
+}
+
+// Schedule captures and saves a snapshot every interval until the context is cancelled
+// Captures are skipped while the object cache has not completed its initial sync
+func Schedule(ctx context.Context, objectCache *k8s.ObjectCache, store Store, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				if !objectCache.HasSynced() {
+					log.Printf("Skipping scheduled snapshot, object cache not synced yet")
+					continue
+				}
+				if err := store.Save(Capture(objectCache, models.SnapshotTriggerScheduled, now)); err != nil {
+					log.Printf("Error saving scheduled snapshot: %v", err)
+				}
+			}
+		}
+	}()
+}
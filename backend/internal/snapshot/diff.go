@@ -0,0 +1,178 @@
+package snapshot
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/gravitek/crossplane-spy/internal/models"
+)
+
+// Diff reports the objects added, removed and changed between two snapshots
+// Objects are matched by group, kind, namespace and name, so an API version bump is not a change
+func Diff(from, to models.Snapshot) models.SnapshotDiff {
+	diff := models.SnapshotDiff{
+		From:    from.ID,
+		To:      to.ID,
+		Added:   []models.SnapshotObjectRef{},
+		Removed: []models.SnapshotObjectRef{},
+		Changed: []models.ObjectChange{},
+	}
+
+	before := indexObjects(from.Objects)
+	after := indexObjects(to.Objects)
+
+	for _, id := range slices.Sorted(maps.Keys(after)) {
+		if _, ok := before[id]; !ok {
+			diff.Added = append(diff.Added, refOf(after[id]))
+		}
+	}
+
+	for _, id := range slices.Sorted(maps.Keys(before)) {
+		old := before[id]
+		current, ok := after[id]
+		if !ok {
+			diff.Removed = append(diff.Removed, refOf(old))
+			continue
+		}
+
+		change := models.ObjectChange{
+			SnapshotObjectRef: refOf(current),
+			SpecChanges:       DiffFields("spec", old.Spec, current.Spec, nil),
+			ConditionChanges:  diffConditions(old.Status.Conditions, current.Status.Conditions),
+		}
+		if len(change.SpecChanges) > 0 || len(change.ConditionChanges) > 0 {
+			diff.Changed = append(diff.Changed, change)
+		}
+	}
+
+	return diff
+}
+
+// indexObjects indexes snapshot objects by their version-independent identity
+func indexObjects(objects []models.SnapshotObject) map[string]models.SnapshotObject {
+	index := make(map[string]models.SnapshotObject, len(objects))
+	for _, obj := range objects {
+		index[objectID(refOf(obj))] = obj
+	}
+	return index
+}
+
+// objectID returns the version-independent identity of an object
+func objectID(ref models.SnapshotObjectRef) string {
+	group := ref.APIVersion
+	if i := strings.LastIndex(group, "/"); i >= 0 {
+		group = group[:i]
+	} else {
+		group = ""
+	}
+	return fmt.Sprintf("%s/%s/%s/%s", group, ref.Kind, ref.Namespace, ref.Name)
+}
+
+// refOf returns the reference of a snapshot object
+func refOf(obj models.SnapshotObject) models.SnapshotObjectRef {
+	return models.SnapshotObjectRef{
+		APIVersion: obj.APIVersion,
+		Kind:       obj.Kind,
+		Namespace:  obj.Metadata.Namespace,
+		Name:       obj.Metadata.Name,
+	}
+}
+
+// DiffFields recursively compares two spec values and appends the changed leaf paths
+// Lists of different lengths are reported as a whole
+func DiffFields(path string, before, after interface{}, changes []models.FieldChange) []models.FieldChange {
+	beforeMap, beforeIsMap := before.(map[string]interface{})
+	afterMap, afterIsMap := after.(map[string]interface{})
+	if beforeIsMap && afterIsMap {
+		keys := make(map[string]bool)
+		for k := range beforeMap {
+			keys[k] = true
+		}
+		for k := range afterMap {
+			keys[k] = true
+		}
+		for _, k := range slices.Sorted(maps.Keys(keys)) {
+			changes = DiffFields(path+"."+k, beforeMap[k], afterMap[k], changes)
+		}
+		return changes
+	}
+
+	beforeList, beforeIsList := before.([]interface{})
+	afterList, afterIsList := after.([]interface{})
+	if beforeIsList && afterIsList && len(beforeList) == len(afterList) {
+		for i := range beforeList {
+			changes = DiffFields(fmt.Sprintf("%s[%d]", path, i), beforeList[i], afterList[i], changes)
+		}
+		return changes
+	}
+
+	if !valuesEqual(before, after) {
+		changes = append(changes, models.FieldChange{Path: path, Before: before, After: after})
+	}
+	return changes
+}
+
+// valuesEqual compares two leaf values, numbers are compared by value since snapshots
+// read back from JSON hold float64 where live objects hold int64
+func valuesEqual(a, b interface{}) bool {
+	if x, ok := toFloat(a); ok {
+		if y, ok := toFloat(b); ok {
+			return x == y
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// diffConditions compares conditions by type, a change of status, reason or message is reported
+func diffConditions(before, after []models.Condition) []models.ConditionChange {
+	beforeByType := make(map[string]models.Condition, len(before))
+	for _, c := range before {
+		beforeByType[c.Type] = c
+	}
+	afterByType := make(map[string]models.Condition, len(after))
+	for _, c := range after {
+		afterByType[c.Type] = c
+	}
+
+	types := make(map[string]bool)
+	for t := range beforeByType {
+		types[t] = true
+	}
+	for t := range afterByType {
+		types[t] = true
+	}
+
+	var changes []models.ConditionChange
+	for _, t := range slices.Sorted(maps.Keys(types)) {
+		old, hadOld := beforeByType[t]
+		current, hasCurrent := afterByType[t]
+		if hadOld && hasCurrent && old.Status == current.Status && old.Reason == current.Reason && old.Message == current.Message {
+			continue
+		}
+
+		change := models.ConditionChange{Type: t}
+		if hadOld {
+			change.Before = &old
+		}
+		if hasCurrent {
+			change.After = &current
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
@@ -0,0 +1,95 @@
+package snapshot
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/gravitek/crossplane-spy/internal/models"
+)
+
+// DefaultMaxSnapshots is the number of snapshots kept by the in-memory store
+const DefaultMaxSnapshots = 10
+
+// Store keeps captured snapshots
+type Store interface {
+	// Save stores a snapshot
+	Save(snapshot models.Snapshot) error
+	// Get returns a snapshot with its objects
+	Get(id string) (models.Snapshot, bool, error)
+	// List returns all snapshots without their objects, oldest first
+	List() ([]models.Snapshot, error)
+	// Delete removes a snapshot, deleting an unknown snapshot is not an error
+	Delete(id string) error
+}
+
+// MemoryStore is an in-memory Store keeping the most recent snapshots
+type MemoryStore struct {
+	maxSnapshots int
+
+	mu        sync.Mutex
+	snapshots []models.Snapshot
+}
+
+// NewMemoryStore creates an in-memory store keeping at most maxSnapshots snapshots
+// A non-positive bound falls back to the default
+func NewMemoryStore(maxSnapshots int) *MemoryStore {
+	if maxSnapshots <= 0 {
+		maxSnapshots = DefaultMaxSnapshots
+	}
+	return &MemoryStore{maxSnapshots: maxSnapshots}
+}
+
+// Save stores a snapshot, evicting the oldest one when the store is full
+func (s *MemoryStore) Save(snapshot models.Snapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.snapshots = append(s.snapshots, snapshot)
+	sort.SliceStable(s.snapshots, func(i, j int) bool {
+		return s.snapshots[i].CreatedAt.Before(s.snapshots[j].CreatedAt)
+	})
+	if len(s.snapshots) > s.maxSnapshots {
+		s.snapshots = s.snapshots[len(s.snapshots)-s.maxSnapshots:]
+	}
+	return nil
+}
+
+// Get returns a snapshot with its objects
+func (s *MemoryStore) Get(id string) (models.Snapshot, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, snapshot := range s.snapshots {
+		if snapshot.ID == id {
+			return snapshot, true, nil
+		}
+	}
+	return models.Snapshot{}, false, nil
+}
+
+// List returns all snapshots without their objects, oldest first
+func (s *MemoryStore) List() ([]models.Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	summaries := make([]models.Snapshot, 0, len(s.snapshots))
+	for _, snapshot := range s.snapshots {
+		snapshot.Objects = nil
+		summaries = append(summaries, snapshot)
+	}
+	return summaries, nil
+}
+
+// Delete removes a snapshot
+func (s *MemoryStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, snapshot := range s.snapshots {
+		if snapshot.ID == id {
+			s.snapshots = append(s.snapshots[:i], s.snapshots[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
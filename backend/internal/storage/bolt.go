@@ -0,0 +1,215 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"log"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// timelineBucket is a bucket keyed by record time, subject to retention
+// Records can be looked up through an index bucket, whose entries are removed with them
+type timelineBucket struct {
+	name  []byte
+	index []byte
+	// indexKey returns the index key of a record
+	indexKey func(id, value []byte) ([]byte, bool)
+}
+
+// timelineBuckets are the buckets of the database
+var timelineBuckets = []timelineBucket{
+	{name: transitionsBucket, index: transitionsIndexBucket, indexKey: transitionIndexKey},
+	{name: eventsBucket, index: eventsIndexBucket, indexKey: eventIndexKey},
+	{name: snapshotsBucket, index: snapshotsIndexBucket, indexKey: snapshotIndexKey},
+}
+
+// Default retention policy
+const (
+	DefaultRetention         = 7 * 24 * time.Hour
+	DefaultMaxSizeBytes      = 512 * 1024 * 1024
+	DefaultRetentionInterval = 10 * time.Minute
+)
+
+// Options configures the persistent store
+type Options struct {
+	// Retention is how long records are kept, zero keeps them forever
+	Retention time.Duration
+	// MaxSizeBytes caps the size of the stored records, the oldest are deleted first
+	// Zero disables the cap
+	MaxSizeBytes int64
+}
+
+// DB is an embedded BoltDB store persisting condition transitions, Events and snapshots across restarts
+// It implements history.Store and snapshot.Store
+type DB struct {
+	db   *bolt.DB
+	opts Options
+}
+
+// Open opens or creates the database file at path
+func Open(path string, opts Options) (*DB, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open store %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, b := range timelineBuckets {
+			if _, err := tx.CreateBucketIfNotExists(b.name); err != nil {
+				return err
+			}
+			if b.index != nil {
+				if _, err := tx.CreateBucketIfNotExists(b.index); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize store %s: %w", path, err)
+	}
+
+	return &DB{db: db, opts: opts}, nil
+}
+
+// Close closes the database file
+func (d *DB) Close() error {
+	return d.db.Close()
+}
+
+// StartRetention periodically applies the retention window and size cap until the context is cancelled
+func (d *DB) StartRetention(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := d.applyRetention(time.Now()); err != nil {
+				log.Printf("Error applying store retention: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// applyRetention deletes records older than the retention window,
+// then the oldest records until the stored data fits in the size cap
+func (d *DB) applyRetention(now time.Time) error {
+	return d.db.Update(func(tx *bolt.Tx) error {
+		if d.opts.Retention > 0 {
+			cutoff := timeKey(now.Add(-d.opts.Retention), 0)
+			for _, b := range timelineBuckets {
+				if err := deleteOldest(tx, b, func(id []byte) bool {
+					return bytes.Compare(id, cutoff) < 0
+				}); err != nil {
+					return err
+				}
+			}
+		}
+
+		if d.opts.MaxSizeBytes <= 0 {
+			return nil
+		}
+
+		// Bucket statistics only reflect committed pages, so the share of records to delete
+		// is computed once from the current size, with a margin to avoid deleting on every run
+		size := dataSize(tx)
+		if size <= d.opts.MaxSizeBytes {
+			return nil
+		}
+		share := float64(size-d.opts.MaxSizeBytes)/float64(size) + 0.1
+
+		for _, b := range timelineBuckets {
+			remaining := int(float64(tx.Bucket(b.name).Stats().KeyN)*share) + 1
+			if err := deleteOldest(tx, b, func(id []byte) bool {
+				remaining--
+				return remaining >= 0
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// deleteOldest deletes records from the start of a time-keyed bucket while shouldDelete returns true,
+// removing their index entries as well
+func deleteOldest(tx *bolt.Tx, b timelineBucket, shouldDelete func(id []byte) bool) error {
+	bucket := tx.Bucket(b.name)
+
+	var ids [][]byte
+	cursor := bucket.Cursor()
+	for k, _ := cursor.First(); k != nil && shouldDelete(k); k, _ = cursor.Next() {
+		ids = append(ids, append([]byte(nil), k...))
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if b.index != nil {
+		if err := deleteIndexEntries(tx, b, ids); err != nil {
+			return err
+		}
+	}
+
+	for _, id := range ids {
+		if err := bucket.Delete(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteIndexEntries removes the index entries of the given records
+func deleteIndexEntries(tx *bolt.Tx, b timelineBucket, ids [][]byte) error {
+	bucket := tx.Bucket(b.name)
+	indexBucket := tx.Bucket(b.index)
+
+	for _, id := range ids {
+		value := bucket.Get(id)
+		if value == nil {
+			continue
+		}
+
+		key, ok := b.indexKey(id, value)
+		if !ok {
+			// Undecodable record, its index entry (if any) cannot be found
+			continue
+		}
+		if err := indexBucket.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dataSize returns the bytes used by the records of all buckets
+// The database file itself does not shrink, freed pages are reused by new records
+func dataSize(tx *bolt.Tx) int64 {
+	var size int64
+	_ = tx.ForEach(func(_ []byte, bucket *bolt.Bucket) error {
+		stats := bucket.Stats()
+		size += int64(stats.LeafInuse + stats.InlineBucketInuse)
+		return nil
+	})
+	return size
+}
+
+// timeKey builds a time-sortable record key from a timestamp and a sequence number
+func timeKey(t time.Time, seq uint64) []byte {
+	key := make([]byte, 16)
+	binary.BigEndian.PutUint64(key[:8], uint64(t.UnixNano()))
+	binary.BigEndian.PutUint64(key[8:], seq)
+	return key
+}
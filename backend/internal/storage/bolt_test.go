@@ -0,0 +1,257 @@
+package storage
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gravitek/crossplane-spy/internal/history"
+	"github.com/gravitek/crossplane-spy/internal/models"
+	bolt "go.etcd.io/bbolt"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var testKey = history.ObjectKey{Group: "s3.aws.upbound.io", Kind: "Bucket", Name: "logs-bucket"}
+
+// openTestDB opens a database in a temporary directory, closed at the end of the test
+func openTestDB(t *testing.T, opts Options) *DB {
+	t.Helper()
+	d, err := Open(filepath.Join(t.TempDir(), "test.db"), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { d.Close() })
+	return d
+}
+
+// bucketLen returns the number of keys of a bucket
+func bucketLen(t *testing.T, d *DB, name []byte) int {
+	t.Helper()
+	n := 0
+	err := d.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(name).ForEach(func(_, _ []byte) error {
+			n++
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestRetention(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	ages := []time.Duration{48 * time.Hour, 25 * time.Hour, 23 * time.Hour, time.Hour}
+
+	for _, tc := range []struct {
+		name   string
+		bucket timelineBucket
+		add    func(d *DB, at time.Time, i int) error
+		count  func(d *DB) (int, error)
+	}{
+		{
+			name:   "transitions",
+			bucket: timelineBuckets[0],
+			add: func(d *DB, at time.Time, i int) error {
+				return d.Append(testKey, models.ConditionTransition{Type: "Ready", Status: fmt.Sprint(i), ObservedAt: at})
+			},
+			count: func(d *DB) (int, error) {
+				transitions, err := d.Transitions(testKey)
+				return len(transitions), err
+			},
+		},
+		{
+			name:   "events",
+			bucket: timelineBuckets[1],
+			add: func(d *DB, at time.Time, i int) error {
+				return d.AppendEvent(testKey, models.ObjectEvent{UID: fmt.Sprint(i), Count: 1, ObservedAt: at})
+			},
+			count: func(d *DB) (int, error) {
+				events, err := d.Events(testKey)
+				return len(events), err
+			},
+		},
+		{
+			name:   "snapshots",
+			bucket: timelineBuckets[2],
+			add: func(d *DB, at time.Time, i int) error {
+				return d.Save(models.Snapshot{ID: fmt.Sprintf("snapshot-%d", i), CreatedAt: at})
+			},
+			count: func(d *DB) (int, error) {
+				snapshots, err := d.List()
+				return len(snapshots), err
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			d := openTestDB(t, Options{Retention: 24 * time.Hour})
+			for i, age := range ages {
+				if err := tc.add(d, now.Add(-age), i); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := d.applyRetention(now); err != nil {
+				t.Fatal(err)
+			}
+
+			// Records older than a day are deleted with their index entries
+			if got := bucketLen(t, d, tc.bucket.name); got != 2 {
+				t.Errorf("got %d records, want 2", got)
+			}
+			if got := bucketLen(t, d, tc.bucket.index); got != 2 {
+				t.Errorf("got %d index entries, want 2", got)
+			}
+			if got, err := tc.count(d); err != nil || got != 2 {
+				t.Errorf("got %d readable records (%v), want 2", got, err)
+			}
+		})
+	}
+}
+
+func TestSizeCap(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	message := strings.Repeat("x", 2048)
+
+	for _, tc := range []struct {
+		name string
+		// capShare is the size cap as a share of the stored size
+		capShare float64
+		wantMin  int
+		wantMax  int
+	}{
+		{name: "under the cap", capShare: 2, wantMin: 100, wantMax: 100},
+		// The share of records to delete is the excess plus a 10% margin
+		{name: "over the cap", capShare: 0.5, wantMin: 35, wantMax: 45},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			d := openTestDB(t, Options{})
+			for i := 0; i < 100; i++ {
+				transition := models.ConditionTransition{Type: "Ready", Status: fmt.Sprint(i), Message: message, ObservedAt: start.Add(time.Duration(i) * time.Minute)}
+				if err := d.Append(testKey, transition); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var size int64
+			if err := d.db.View(func(tx *bolt.Tx) error {
+				size = dataSize(tx)
+				return nil
+			}); err != nil {
+				t.Fatal(err)
+			}
+			d.opts.MaxSizeBytes = int64(float64(size) * tc.capShare)
+
+			if err := d.applyRetention(start); err != nil {
+				t.Fatal(err)
+			}
+
+			transitions, err := d.Transitions(testKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(transitions) < tc.wantMin || len(transitions) > tc.wantMax {
+				t.Fatalf("got %d transitions, want %d to %d", len(transitions), tc.wantMin, tc.wantMax)
+			}
+			if got := bucketLen(t, d, transitionsIndexBucket); got != len(transitions) {
+				t.Errorf("got %d index entries for %d transitions", got, len(transitions))
+			}
+			// The oldest are deleted first
+			if last := transitions[len(transitions)-1]; last.Status != "99" {
+				t.Errorf("got last transition %s, want the newest one", last.Status)
+			}
+		})
+	}
+}
+
+func TestSnapshotDelete(t *testing.T) {
+	d := openTestDB(t, Options{})
+	for _, id := range []string{"a", "b"} {
+		if err := d.Save(models.Snapshot{ID: id, CreatedAt: time.Now()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, id := range []string{"a", "unknown"} {
+		if err := d.Delete(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, found, err := d.Get("a"); err != nil || found {
+		t.Errorf("got deleted snapshot: found %v, error %v", found, err)
+	}
+	if got := bucketLen(t, d, snapshotsBucket); got != 1 {
+		t.Errorf("got %d snapshots, want 1", got)
+	}
+	if got := bucketLen(t, d, snapshotsIndexBucket); got != 1 {
+		t.Errorf("got %d index entries, want 1", got)
+	}
+}
+
+// TestRecorderRestart checks that a recorder over a reopened database does not record again
+// the conditions and Events recorded before the restart
+func TestRecorderRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+
+	bucket := func(ready string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"status": map[string]interface{}{"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": ready},
+				map[string]interface{}{"type": "Synced", "status": "True"},
+			}},
+		}}
+		obj.SetAPIVersion("s3.aws.upbound.io/v1beta1")
+		obj.SetKind("Bucket")
+		obj.SetName("logs-bucket")
+		return obj
+	}
+	event := func(uid string, count int32) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: uid, UID: types.UID("uid-" + uid)},
+			InvolvedObject: corev1.ObjectReference{APIVersion: "s3.aws.upbound.io/v1beta1", Kind: "Bucket", Name: "logs-bucket"},
+			Reason:         "CannotObserveExternalResource",
+			Count:          count,
+		}
+	}
+
+	for i, tc := range []struct {
+		name            string
+		ready           string
+		events          map[string]int32
+		wantTransitions int
+		wantEvents      int
+	}{
+		{name: "first run", ready: "False", events: map[string]int32{"throttled": 2}, wantTransitions: 2, wantEvents: 1},
+		{name: "restart without changes", ready: "False", events: map[string]int32{"throttled": 2}, wantTransitions: 2, wantEvents: 1},
+		{name: "restart with changes", ready: "True", events: map[string]int32{"throttled": 3, "created": 1}, wantTransitions: 3, wantEvents: 3},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			d, err := Open(path, Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer d.Close()
+
+			recorder := history.NewRecorder(d)
+			recorder.OnAdd(bucket(tc.ready), true)
+			for uid, count := range tc.events {
+				recorder.OnAdd(event(uid, count), i > 0)
+			}
+
+			transitions, err := d.Transitions(testKey)
+			if err != nil || len(transitions) != tc.wantTransitions {
+				t.Errorf("got %d transitions (%v), want %d", len(transitions), err, tc.wantTransitions)
+			}
+			events, err := d.Events(testKey)
+			if err != nil || len(events) != tc.wantEvents {
+				t.Errorf("got %d events (%v), want %d", len(events), err, tc.wantEvents)
+			}
+		})
+	}
+}
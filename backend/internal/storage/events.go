@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/gravitek/crossplane-spy/internal/history"
+	"github.com/gravitek/crossplane-spy/internal/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Events are keyed by observation time, and indexed by object
+var (
+	eventsBucket      = []byte("events")
+	eventsIndexBucket = []byte("events-by-object")
+)
+
+// eventRecord is the stored form of an Event about an object
+type eventRecord struct {
+	Key   history.ObjectKey  `json:"key"`
+	Event models.ObjectEvent `json:"event"`
+}
+
+// AppendEvent records an Event about an object
+func (d *DB) AppendEvent(key history.ObjectKey, event models.ObjectEvent) error {
+	value, err := json.Marshal(eventRecord{Key: key, Event: event})
+	if err != nil {
+		return err
+	}
+
+	return d.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(eventsBucket)
+		seq, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		id := timeKey(event.ObservedAt, seq)
+		if err := bucket.Put(id, value); err != nil {
+			return err
+		}
+		return tx.Bucket(eventsIndexBucket).Put(indexKey(key, id), id)
+	})
+}
+
+// Events returns the recorded Events of an object, oldest first
+func (d *DB) Events(key history.ObjectKey) ([]models.ObjectEvent, error) {
+	events := []models.ObjectEvent{}
+
+	err := d.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(eventsBucket)
+		prefix := objectPrefix(key)
+
+		cursor := tx.Bucket(eventsIndexBucket).Cursor()
+		for k, id := cursor.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, id = cursor.Next() {
+			value := bucket.Get(id)
+			if value == nil {
+				continue
+			}
+
+			var record eventRecord
+			if err := json.Unmarshal(value, &record); err != nil {
+				return err
+			}
+			events = append(events, record.Event)
+		}
+		return nil
+	})
+
+	return events, err
+}
+
+// eventIndexKey returns the index key of a stored Event
+func eventIndexKey(id, value []byte) ([]byte, bool) {
+	var record eventRecord
+	if err := json.Unmarshal(value, &record); err != nil {
+		return nil, false
+	}
+	return indexKey(record.Key, id), true
+}
@@ -0,0 +1,189 @@
+package storage
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+
+	"github.com/gravitek/crossplane-spy/internal/models"
+	"github.com/gravitek/crossplane-spy/internal/snapshot"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Snapshots are keyed by creation time, and indexed by ID
+// The index entries hold the snapshot summaries, so listing does not read the objects
+var (
+	snapshotsBucket      = []byte("snapshots")
+	snapshotsIndexBucket = []byte("snapshots-by-id")
+)
+
+var _ snapshot.Store = &DB{}
+
+// snapshotIndexEntry is the value of a snapshot index entry
+type snapshotIndexEntry struct {
+	Key     []byte          `json:"key"`
+	Summary models.Snapshot `json:"summary"`
+}
+
+// Save stores a snapshot, its objects are gzip-compressed
+func (d *DB) Save(s models.Snapshot) error {
+	data, err := encodeSnapshot(s)
+	if err != nil {
+		return err
+	}
+
+	return d.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(snapshotsBucket)
+		index := tx.Bucket(snapshotsIndexBucket)
+
+		// Replace a snapshot saved with the same ID
+		if existing := index.Get([]byte(s.ID)); existing != nil {
+			var entry snapshotIndexEntry
+			if err := json.Unmarshal(existing, &entry); err == nil {
+				if err := bucket.Delete(entry.Key); err != nil {
+					return err
+				}
+			}
+		}
+
+		seq, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		id := timeKey(s.CreatedAt, seq)
+
+		summary := s
+		summary.Objects = nil
+		entry, err := json.Marshal(snapshotIndexEntry{Key: id, Summary: summary})
+		if err != nil {
+			return err
+		}
+
+		if err := bucket.Put(id, data); err != nil {
+			return err
+		}
+		return index.Put([]byte(s.ID), entry)
+	})
+}
+
+// Get returns a snapshot with its objects
+func (d *DB) Get(id string) (models.Snapshot, bool, error) {
+	var s models.Snapshot
+	found := false
+
+	err := d.db.View(func(tx *bolt.Tx) error {
+		raw := tx.Bucket(snapshotsIndexBucket).Get([]byte(id))
+		if raw == nil {
+			return nil
+		}
+
+		var entry snapshotIndexEntry
+		if err := json.Unmarshal(raw, &entry); err != nil {
+			return err
+		}
+
+		data := tx.Bucket(snapshotsBucket).Get(entry.Key)
+		if data == nil {
+			return nil
+		}
+
+		decoded, err := decodeSnapshot(data)
+		if err != nil {
+			return err
+		}
+		s, found = decoded, true
+		return nil
+	})
+
+	return s, found, err
+}
+
+// List returns all snapshots without their objects, oldest first
+func (d *DB) List() ([]models.Snapshot, error) {
+	summaries := []models.Snapshot{}
+
+	err := d.db.View(func(tx *bolt.Tx) error {
+		// IDs sort chronologically
+		return tx.Bucket(snapshotsIndexBucket).ForEach(func(_, raw []byte) error {
+			var entry snapshotIndexEntry
+			if err := json.Unmarshal(raw, &entry); err != nil {
+				return err
+			}
+			summaries = append(summaries, entry.Summary)
+			return nil
+		})
+	})
+
+	return summaries, err
+}
+
+// Delete removes a snapshot
+func (d *DB) Delete(id string) error {
+	return d.db.Update(func(tx *bolt.Tx) error {
+		index := tx.Bucket(snapshotsIndexBucket)
+		raw := index.Get([]byte(id))
+		if raw == nil {
+			return nil
+		}
+
+		var entry snapshotIndexEntry
+		if err := json.Unmarshal(raw, &entry); err == nil {
+			if err := tx.Bucket(snapshotsBucket).Delete(entry.Key); err != nil {
+				return err
+			}
+		}
+		return index.Delete([]byte(id))
+	})
+}
+
+// snapshotIndexKey returns the index key of a stored snapshot, read from the record header
+func snapshotIndexKey(_, value []byte) ([]byte, bool) {
+	if len(value) < 2 {
+		return nil, false
+	}
+	n := int(binary.BigEndian.Uint16(value[:2]))
+	if len(value) < 2+n {
+		return nil, false
+	}
+	return value[2 : 2+n], true
+}
+
+// encodeSnapshot encodes a snapshot record: the ID length and ID, followed by the gzipped JSON
+func encodeSnapshot(s models.Snapshot) ([]byte, error) {
+	var buf bytes.Buffer
+
+	header := make([]byte, 2)
+	binary.BigEndian.PutUint16(header, uint16(len(s.ID)))
+	buf.Write(header)
+	buf.WriteString(s.ID)
+
+	zw := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(zw).Encode(s); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeSnapshot decodes a snapshot record
+func decodeSnapshot(data []byte) (models.Snapshot, error) {
+	var s models.Snapshot
+
+	id, ok := snapshotIndexKey(nil, data)
+	if !ok {
+		return s, io.ErrUnexpectedEOF
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(data[2+len(id):]))
+	if err != nil {
+		return s, err
+	}
+	defer zr.Close()
+
+	err = json.NewDecoder(zr).Decode(&s)
+	return s, err
+}
@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gravitek/crossplane-spy/internal/history"
+	"github.com/gravitek/crossplane-spy/internal/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Transitions are keyed by observation time, and indexed by object
+var (
+	transitionsBucket      = []byte("transitions")
+	transitionsIndexBucket = []byte("transitions-by-object")
+)
+
+var _ history.Store = &DB{}
+
+// transitionRecord is the stored form of a condition transition
+type transitionRecord struct {
+	Key        history.ObjectKey          `json:"key"`
+	Transition models.ConditionTransition `json:"transition"`
+}
+
+// Append records a transition of an object
+func (d *DB) Append(key history.ObjectKey, transition models.ConditionTransition) error {
+	value, err := json.Marshal(transitionRecord{Key: key, Transition: transition})
+	if err != nil {
+		return err
+	}
+
+	return d.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(transitionsBucket)
+		seq, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		id := timeKey(transition.ObservedAt, seq)
+		if err := bucket.Put(id, value); err != nil {
+			return err
+		}
+		return tx.Bucket(transitionsIndexBucket).Put(indexKey(key, id), id)
+	})
+}
+
+// Transitions returns the recorded transitions of an object, oldest first
+func (d *DB) Transitions(key history.ObjectKey) ([]models.ConditionTransition, error) {
+	transitions := []models.ConditionTransition{}
+
+	err := d.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(transitionsBucket)
+		prefix := objectPrefix(key)
+
+		cursor := tx.Bucket(transitionsIndexBucket).Cursor()
+		for k, id := cursor.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, id = cursor.Next() {
+			value := bucket.Get(id)
+			if value == nil {
+				continue
+			}
+
+			var record transitionRecord
+			if err := json.Unmarshal(value, &record); err != nil {
+				return err
+			}
+			transitions = append(transitions, record.Transition)
+		}
+		return nil
+	})
+
+	return transitions, err
+}
+
+// transitionIndexKey returns the index key of a stored transition
+func transitionIndexKey(id, value []byte) ([]byte, bool) {
+	var record transitionRecord
+	if err := json.Unmarshal(value, &record); err != nil {
+		return nil, false
+	}
+	return indexKey(record.Key, id), true
+}
+
+// objectPrefix is the index key prefix of an object
+func objectPrefix(key history.ObjectKey) []byte {
+	return []byte(fmt.Sprintf("%s\x00%s\x00%s\x00%s\x00", key.Group, key.Kind, key.Namespace, key.Name))
+}
+
+// indexKey is the index key of a record of an object, ordered by time within the object
+func indexKey(key history.ObjectKey, id []byte) []byte {
+	return append(objectPrefix(key), id...)
+}
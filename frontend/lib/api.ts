@@ -25,17 +25,58 @@ export const api = {
   getResources: () => fetchAPI("/resources"),
   getResourcesByKind: (kind: string) => fetchAPI(`/resources/${kind}`),
   getResource: (kind: string, namespace: string, name: string) =>
-    fetchAPI(`/resources/${kind}/${namespace}/${name}`),
+    fetchAPI(`/resources/${kind}/${namespace || "_"}/${name}`),
+  getResourceHistory: (kind: string, namespace: string, name: string) =>
+    fetchAPI(`/resources/${kind}/${namespace || "_"}/${name}/history`),
+  getResourceRawURL: (kind: string, namespace: string, name: string, format: "json" | "yaml" = "yaml") =>
+    `${API_BASE_URL}/resources/${kind}/${namespace || "_"}/${name}/raw?format=${format}`,
 
   // Specific resource type endpoints
   getProviders: () => fetchAPI("/providers"),
+  getProvider: (name: string) => fetchAPI(`/providers/${encodeURIComponent(name)}`),
+  getProviderLogsURL: (name: string, follow = true, tailLines = 100) =>
+    `${API_BASE_URL}/providers/${encodeURIComponent(name)}/logs?follow=${follow}&tailLines=${tailLines}`,
   getProviderConfigs: () => fetchAPI("/providerconfigs"),
   getXRDs: () => fetchAPI("/xrds"),
   getCompositions: () => fetchAPI("/compositions"),
+  getComposition: (name: string) => fetchAPI(`/compositions/${encodeURIComponent(name)}`),
+  getCompositionRevisions: (name: string) => fetchAPI(`/compositions/${encodeURIComponent(name)}/revisions`),
+  getCompositionLint: (name: string) => fetchAPI(`/compositions/${encodeURIComponent(name)}/lint`),
+  getEnvironmentConfigs: () => fetchAPI("/environmentconfigs"),
+  getEnvironmentConfig: (name: string) => fetchAPI(`/environmentconfigs/${encodeURIComponent(name)}`),
   getXRs: () => fetchAPI("/xrs"),
   getFunctions: () => fetchAPI("/functions"),
+  getFunction: (name: string) => fetchAPI(`/functions/${encodeURIComponent(name)}`),
+  getFunctionLogsURL: (name: string, follow = true, tailLines = 100) =>
+    `${API_BASE_URL}/functions/${encodeURIComponent(name)}/logs?follow=${follow}&tailLines=${tailLines}`,
+  getUsages: () => fetchAPI("/usages"),
 
   // Scope-based endpoints
   getClusterResources: () => fetchAPI("/cluster-resources"),
   getNamespaceResources: () => fetchAPI("/namespace-resources"),
+
+  // Full-text search
+  search: (query: string) => fetchAPI(`/search?q=${encodeURIComponent(query)}`),
+
+  // Snapshots
+  getSnapshots: () => fetchAPI("/snapshots"),
+  getSnapshot: (id: string) => fetchAPI(`/snapshots/${encodeURIComponent(id)}`),
+  createSnapshot: async () => {
+    const response = await fetch(`${API_BASE_URL}/snapshots`, { method: "POST" });
+    if (!response.ok) {
+      throw new Error(`API error: ${response.statusText}`);
+    }
+    return response.json();
+  },
+  diffSnapshots: (from: string, to: string = "live") =>
+    fetchAPI(`/snapshots/diff?from=${encodeURIComponent(from)}&to=${encodeURIComponent(to)}`),
+
+  // Reports
+  getSyncFailuresReport: (provider?: string) =>
+    fetchAPI(provider ? `/reports/sync-failures?provider=${encodeURIComponent(provider)}` : "/reports/sync-failures"),
+  getStuckDeletionsReport: () => fetchAPI("/reports/stuck-deletions"),
+  getOrphansReport: () => fetchAPI("/reports/orphans"),
+  getPausedReport: () => fetchAPI("/reports/paused"),
+  getPolicyAuditReport: () => fetchAPI("/reports/policies"),
+  getUpgradeReport: () => fetchAPI("/reports/v2-upgrade"),
 };
@@ -69,3 +69,18 @@ Create the namespace
 {{- .Release.Namespace }}
 {{- end }}
 {{- end }}
+
+{{/*
+Interval between scheduled snapshots as a Go duration
+An empty value or a bare 0 disables them, other values need a unit
+*/}}
+{{- define "crossplane-spy.snapshotInterval" -}}
+{{- $interval := toString (.Values.store.snapshotInterval | default "0s") }}
+{{- if eq $interval "0" }}
+{{- $interval = "0s" }}
+{{- end }}
+{{- if not (regexMatch "^([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$" $interval) }}
+{{- fail (printf "store.snapshotInterval must be a duration with a unit, e.g. 30m or 1h, got %q" $interval) }}
+{{- end }}
+{{- $interval }}
+{{- end }}